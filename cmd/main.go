@@ -2,12 +2,19 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"blackjack/internal/count"
+	"blackjack/internal/deck"
 	"blackjack/internal/game"
 	"blackjack/internal/rules"
+	"blackjack/internal/strategy"
 )
 
 // clearScreen clears the terminal screen
@@ -15,47 +22,316 @@ func clearScreen() {
 	fmt.Print("\033[H\033[2J") // ANSI escape code to clear screen
 }
 
-// getPlayerName prompts for and returns the player's name
-func getPlayerName() string {
+// getPlayerNames prompts for and returns the players' names
+// Several names separated by commas seat several people for hot-seat play
+func getPlayerNames() []string {
 	reader := bufio.NewReader(os.Stdin) //bufio is a package that provides buffered I/O. It's used to read input from the user.
-	fmt.Print("\nEnter your name: ")
-	name, _ := reader.ReadString('\n')
-	return strings.TrimSpace(name)
+	fmt.Printf("\nEnter your name (or up to %d names separated by commas for hot-seat play): ", game.MaxSeats)
+	input, _ := reader.ReadString('\n')
+
+	var names []string
+	for _, name := range strings.Split(input, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, "") // A single player may go nameless
+	}
+	return names
 }
 
-// getPlayerInput reads and returns the player's command
-func getPlayerInput() string {
+// turnPrefix names the player whose turn it is when several share the table
+func turnPrefix(g *game.Game) string {
+	seats := g.Seats()
+	if len(seats) < 2 {
+		return ""
+	}
+	return seats[g.Turn()].Player.Name + " - "
+}
+
+// getPlayerInput reads and returns the player's command, offering only the legal actions
+func getPlayerInput(prefix string, legal rules.Actions) string {
+	commands := []string{"h/hit", "s/stand"}
+	if legal.Double {
+		commands = append(commands, "d/double")
+	}
+	if legal.Split {
+		commands = append(commands, "p/split")
+	}
+	if legal.Surrender {
+		commands = append(commands, "u/surrender")
+	}
+	commands = append(commands, "?/hint", "r/rules", "q/quit")
+
 	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("\nEnter command (h/hit, s/stand, r/rules, q/quit): ")
+	fmt.Printf("\n%sEnter command (%s): ", prefix, strings.Join(commands, ", "))
 	input, _ := reader.ReadString('\n')
 	return strings.ToLower(strings.TrimSpace(input))
 }
 
+// getBets asks every seat for a wager, in turn order. Players who cannot
+// cover the table minimum leave the table. It returns false once nobody can play on.
+func getBets(g *game.Game, lastBets map[string]int) bool {
+	table := g.Rules()
+	for _, seat := range append([]*game.Seat(nil), g.Seats()...) {
+		name := seat.Player.Name
+		if seat.Player.Bankroll+seat.Player.Bet < table.MinBet {
+			fmt.Printf("\n%s has %d chips left, which is below the table minimum of %d.\n", displayName(name), seat.Player.Bankroll, table.MinBet)
+			if g.Leave(name) != nil {
+				return false // The last player at the table cannot leave it empty
+			}
+			continue
+		}
+
+		lastBet := lastBets[name]
+		getBet(g, seat, &lastBet)
+		lastBets[name] = lastBet
+	}
+	return true
+}
+
+// displayName returns the name to address a player by
+func displayName(name string) string {
+	if name == "" {
+		return "You"
+	}
+	return name
+}
+
+// getBet prompts for a seat's wager until a valid one is placed, offering the last bet as the default
+func getBet(g *game.Game, seat *game.Seat, lastBet *int) {
+	table := g.Rules()
+	bankroll := seat.Player.Bankroll + seat.Player.Bet
+	if *lastBet < table.MinBet || *lastBet > bankroll {
+		*lastBet = table.MinBet
+	}
+
+	prefix := ""
+	if len(g.Seats()) > 1 {
+		prefix = seat.Player.Name + " - "
+	}
+	index := 0
+	for i, s := range g.Seats() {
+		if s == seat {
+			index = i
+		}
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Printf("\n%sBankroll: %d chips. Enter your bet (%d-%d) [%d]: ", prefix, bankroll, table.MinBet, table.MaxBet, *lastBet)
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+
+		amount := *lastBet
+		if input != "" {
+			var err error
+			if amount, err = strconv.Atoi(input); err != nil {
+				fmt.Println("Please enter a whole number of chips.")
+				continue
+			}
+		}
+
+		if err := g.PlaceSeatBet(index, amount); err != nil {
+			fmt.Printf("Invalid bet: %v\n", err)
+			continue
+		}
+		*lastBet = amount
+		return
+	}
+}
+
+// getInsurance asks whether to insure against a dealer BlackJack, or to take
+// even money on a BlackJack, and records the decision
+func getInsurance(g *game.Game) {
+	reader := bufio.NewReader(os.Stdin)
+	prefix := turnPrefix(g)
+	if g.EvenMoneyOffered() {
+		fmt.Printf("\n%sDealer shows an Ace. Take even money for your BlackJack? (y/n): ", prefix)
+		input, _ := reader.ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(input)) == "y" {
+			g.TakeEvenMoney()
+			return
+		}
+		g.DeclineInsurance()
+		return
+	}
+
+	for {
+		fmt.Printf("\n%sDealer shows an Ace. Insurance bet (0-%d, 0 to decline) [0]: ", prefix, g.MaxInsurance())
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+		if input == "" || input == "0" {
+			g.DeclineInsurance()
+			return
+		}
+
+		amount, err := strconv.Atoi(input)
+		if err != nil {
+			fmt.Println("Please enter a whole number of chips.")
+			continue
+		}
+		if err := g.PlaceInsurance(amount); err != nil {
+			fmt.Printf("Invalid insurance: %v\n", err)
+			continue
+		}
+		return
+	}
+}
+
 // displayGameState shows the current state of the game
 func displayGameState(g *game.Game) { //*
 	clearScreen()
 	fmt.Println("\n=== BLACKJACK ===")
+	if commitment := g.ShuffleCommitment(); commitment != "" {
+		fmt.Printf("Shoe commitment: %s\n", commitment)
+	}
 	fmt.Println(g.String())
+	if g.GetState() != game.PlayerTurn {
+		return
+	}
+	if len(g.Seats()) > 1 {
+		fmt.Printf("\n%s to play\n", g.Seats()[g.Turn()].Player.Name)
+	}
+	if count, active := g.Hands(); count > 1 {
+		fmt.Printf("Playing hand %d of %d\n", active, count)
+	}
 }
 
-// playRound plays a single round of BlackJack
-func playRound(g *game.Game) bool {
-	err := g.StartRound()
+// displayRoundNet shows the chips each seat won or lost in the round just settled
+func displayRoundNet(g *game.Game) {
+	for _, seat := range g.Seats() {
+		who, bankroll := "You", seat.Player.Bankroll
+		if len(g.Seats()) > 1 {
+			who = seat.Player.Name
+		}
+
+		switch net := seat.RoundNet; {
+		case net > 0:
+			fmt.Printf("%s won %d chips. Bankroll: %d chips\n", who, net, bankroll)
+		case net < 0:
+			fmt.Printf("%s lost %d chips. Bankroll: %d chips\n", who, -net, bankroll)
+		case len(g.Seats()) > 1:
+			fmt.Printf("%s - Bankroll: %d chips\n", who, bankroll)
+		default:
+			fmt.Printf("Bankroll: %d chips\n", bankroll)
+		}
+	}
+}
+
+// displayShuffleProof reveals the finished shoe and publishes the commitment for the new one
+func displayShuffleProof(g *game.Game) {
+	if proof, ok := g.RevealShuffle(); ok {
+		fmt.Printf("Previous shoe order: %s\n", proof.Order)
+		fmt.Printf("Salt: %s\n", proof.Salt)
+		fmt.Printf("Commitment: %s (verified: %t)\n", proof.Commitment, proof.Verify())
+	}
+	if commitment := g.ShuffleCommitment(); commitment != "" {
+		fmt.Printf("New shoe commitment: %s\n", commitment)
+	}
+}
+
+// overlays holds the optional extras shown while playing
+type overlays struct {
+	coach bool // Point out plays that stray from basic strategy
+	odds  bool // Show the dealer's chances from the cards left
+	ev    bool // Show the exact value of each play from the cards left
+	count bool // Show the running and true count of the cards seen
+}
+
+// displayCount shows the count of every card seen since the shoe was shuffled
+func displayCount(g *game.Game) {
+	fmt.Printf("\n%s (%d cards seen)\n", g.Counter(), g.Counter().Seen())
+}
+
+// displayDealerOdds shows the dealer's chances of busting, and of each total,
+// worked out from the cards the players haven't seen
+func displayDealerOdds(g *game.Game) {
+	d, err := g.DealerOdds()
+	if err != nil {
+		return
+	}
+	fmt.Printf("\nDealer busts %.0f%% from here (%s)\n", 100*d.Bust, d)
+}
+
+// displayActionEV shows the expected value of each legal play, best first,
+// worked out from the exact cards in the hand and those the players haven't seen
+func displayActionEV(g *game.Game) {
+	evs, err := g.ExactEV()
 	if err != nil {
-		fmt.Printf("Error starting round: %v\n", err)
-		return false
+		return
+	}
+	actions := make([]strategy.Action, 0, len(evs))
+	for action := range evs {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		if evs[actions[i]] != evs[actions[j]] {
+			return evs[actions[i]] > evs[actions[j]]
+		}
+		return actions[i] < actions[j]
+	})
+
+	parts := make([]string, len(actions))
+	for i, action := range actions {
+		parts[i] = fmt.Sprintf("%s %+.1f%%", action, 100*evs[action])
+	}
+	fmt.Printf("\nExpected value: %s\n", strings.Join(parts, ", "))
+}
+
+// playRound plays a single round of BlackJack
+// A round already in progress (from a resumed session) is continued instead
+func playRound(g *game.Game, lastBets map[string]int, extras overlays) bool {
+	if !g.RoundInProgress() {
+		if extras.count {
+			displayCount(g) // Counters size their bets from the count before the deal
+		}
+		if !getBets(g, lastBets) {
+			fmt.Println("\nNobody is left at the table.")
+			return false
+		}
+		err := g.StartRound()
+		if err != nil {
+			fmt.Printf("Error starting round: %v\n", err)
+			return false
+		}
 	}
 
 	// Main game loop
+	notice := "" // Hints and coaching, shown once under the table
 	for {
 		displayGameState(g)
+		if notice != "" {
+			fmt.Println("\n" + notice)
+			notice = ""
+		}
+		if extras.odds {
+			displayDealerOdds(g)
+		}
+		if extras.ev {
+			displayActionEV(g)
+		}
+		if extras.count {
+			displayCount(g)
+		}
 
 		// Check if player's turn is over
 		if g.GetState() == game.RoundOver {
 			fmt.Println("\n" + g.GetResult())
+			displayRoundNet(g)
+			if g.Shuffled() {
+				fmt.Println("\nThe shoe has been reshuffled.")
+				displayShuffleProof(g)
+			}
 			return true
 		}
 
+		if g.GetState() == game.InsuranceOffered {
+			getInsurance(g)
+			continue
+		}
+
 		if g.GetState() == game.DealerTurn {
 			fmt.Println("\nDealer's turn...")
 			err := g.DealerPlay()
@@ -67,24 +343,48 @@ func playRound(g *game.Game) bool {
 		}
 
 		// Get player command
-		cmd := getPlayerInput()
+		cmd := getPlayerInput(turnPrefix(g), g.LegalActions())
+		feedback := ""
+		if action, ok := commandActions[cmd]; ok && extras.coach {
+			feedback = coachFeedback(g, action) // Judged before the cards change
+		}
+
+		var err error
 		switch cmd {
 		case "h", "hit":
-			err := g.PlayerHit()
-			if err != nil {
+			if err = g.PlayerHit(); err != nil {
 				fmt.Printf("Error hitting: %v\n", err)
 			}
 
+		case "d", "double":
+			if err = g.PlayerDouble(); err != nil {
+				fmt.Printf("Error doubling: %v\n", err)
+			}
+
+		case "p", "split":
+			if err = g.PlayerSplit(); err != nil {
+				fmt.Printf("Error splitting: %v\n", err)
+			}
+
+		case "u", "surrender":
+			if err = g.PlayerSurrender(); err != nil {
+				fmt.Printf("Error surrendering: %v\n", err)
+			}
+
 		case "s", "stand":
-			err := g.PlayerStand()
-			if err != nil {
+			if err = g.PlayerStand(); err != nil {
 				fmt.Printf("Error standing: %v\n", err)
 			}
 
+		case "?", "hint":
+			if hint, err := g.Hint(); err == nil {
+				notice = fmt.Sprintf("Hint: basic strategy says %s.", hint)
+			}
+
 		case "r", "rules":
 			clearScreen()
-			fmt.Println(rules.DisplayAllRules())
-			fmt.Println(rules.DisplayHelp())
+			fmt.Println(rules.DisplayAllRules(g.Rules()))
+			fmt.Println(rules.DisplayHelp(g.LegalActions()))
 			fmt.Println("\nPress Enter to continue...")
 			bufio.NewReader(os.Stdin).ReadString('\n')
 
@@ -94,32 +394,159 @@ func playRound(g *game.Game) bool {
 		default:
 			fmt.Println("Invalid command. Try again.")
 		}
+		if err == nil && feedback != "" {
+			notice = feedback
+		}
 	}
 }
 
-func main() {
-	clearScreen()
-	fmt.Println(rules.DisplayAllRules())
-	fmt.Println(rules.DisplayHelp())
-	fmt.Println("\nPress Enter to start...")
-	bufio.NewReader(os.Stdin).ReadString('\n')
+// commandActions maps the play commands to the actions basic strategy recommends
+var commandActions = map[string]strategy.Action{
+	"h": strategy.Hit, "hit": strategy.Hit,
+	"s": strategy.Stand, "stand": strategy.Stand,
+	"d": strategy.Double, "double": strategy.Double,
+	"p": strategy.Split, "split": strategy.Split,
+	"u": strategy.Surrender, "surrender": strategy.Surrender,
+}
 
-	name := getPlayerName()
-	g := game.NewGame(name)
+// coachFeedback compares a decision with basic strategy before it is made,
+// explaining what a deviation gives up. It returns "" when the play is correct.
+func coachFeedback(g *game.Game, action strategy.Action) string {
+	best, err := g.Hint()
+	if err != nil || action == best {
+		return ""
+	}
 
-	// Main game loop
+	feedback := fmt.Sprintf("Coach: basic strategy was to %s, not %s.", best, action)
+	evs, err := g.ActionEV()
+	if lost := evs[best] - evs[action]; err == nil && lost > 0 {
+		feedback += fmt.Sprintf(" That gives up about %.1f%% of your bet on average.", 100*lost)
+	}
+	return feedback
+}
+
+// nextRound asks whether to play another round, letting players join or leave the table first
+func nextRound(g *game.Game, bankroll int) bool {
+	reader := bufio.NewReader(os.Stdin)
 	for {
-		if !playRound(g) {
-			break
+		fmt.Print("\nPlay another round? (y/n, or j to join / l to leave the table): ")
+		input, _ := reader.ReadString('\n')
+
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "y":
+			return len(g.Seats()) > 0
+		case "j", "join":
+			fmt.Print("Name of the player joining: ")
+			name, _ := reader.ReadString('\n')
+			if err := g.SitDown(strings.TrimSpace(name), bankroll); err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+		case "l", "leave":
+			fmt.Print("Name of the player leaving: ")
+			name, _ := reader.ReadString('\n')
+			if err := g.Leave(strings.TrimSpace(name)); err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+		default:
+			return false
+		}
+	}
+}
+
+func main() {
+	decks := flag.Int("decks", 1, "number of decks in the shoe (1, 2, 4, 6 or 8)")
+	penetration := flag.Float64("penetration", deck.DefaultPenetration, "percentage of the shoe dealt before reshuffling")
+	seed := flag.Int64("seed", 0, "seed for shuffling, to replay the same game (0 picks a random seed)")
+	secure := flag.Bool("secure", false, "shuffle with crypto/rand and publish a commitment to each shoe")
+	resume := flag.String("resume", "", "resume the session saved in this file")
+	h17 := flag.Bool("h17", false, "dealer hits soft 17 (default: dealer stands on soft 17)")
+	noPeek := flag.Bool("no-peek", false, "European no-hole-card game: the dealer takes a second card only after you play")
+	bankroll := flag.Int("bankroll", game.DefaultBankroll, "chips to start a new session with")
+	coach := flag.Bool("coach", false, "point out plays that stray from basic strategy and what they cost")
+	showOdds := flag.Bool("odds", false, "show the dealer's exact chances from the cards left in the shoe")
+	showEV := flag.Bool("ev", false, "show the exact expected value of each play from the cards left in the shoe")
+	countName := flag.String("count", "", "show the running and true count under this system: "+strings.Join(count.SystemNames(), ", "))
+	train := flag.Bool("trainer", false, "practise card counting instead of playing (uses --count, --decks, --speed and --seed)")
+	speed := flag.Duration("speed", time.Second, "how long the counting trainer shows each card")
+	table := rules.DefaultTableRules()
+	flag.TextVar(&table.BlackjackPayout, "payout", table.BlackjackPayout, "what a BlackJack pays: 3:2, 6:5 or 1:1")
+	flag.TextVar(&table.Double, "double", table.Double, "which hands may be doubled: any, 9-11 or 10-11")
+	flag.TextVar(&table.Surrender, "surrender", table.Surrender, "when a hand may be surrendered: none, late or early")
+	flag.IntVar(&table.MinBet, "min-bet", table.MinBet, "table minimum bet")
+	flag.IntVar(&table.MaxBet, "max-bet", table.MaxBet, "table maximum bet")
+	flag.Parse()
+
+	table.Decks = *decks
+	table.Penetration = *penetration
+	table.DealerHitsSoft17 = *h17
+	table.DealerPeeks = !*noPeek
+	if err := table.Validate(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	system, counting := count.Systems[*countName]
+	if *countName != "" && !counting {
+		fmt.Printf("Error: unknown counting system %q (choose from %s)\n", *countName, strings.Join(count.SystemNames(), ", "))
+		os.Exit(1)
+	}
+
+	if *train {
+		if *speed < 0 {
+			fmt.Printf("Error: invalid speed: %s\n", *speed)
+			os.Exit(1)
 		}
+		if !counting {
+			system = count.HiLo
+		}
+		newTrainer(system, table.Decks, *speed, *seed).run()
+		return
+	}
 
-		// Ask to play another round
-		fmt.Print("\nPlay another round? (y/n): ")
-		input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
-		if strings.ToLower(strings.TrimSpace(input)) != "y" {
-			break
+	// A resumed session keeps its own table and bankrolls; only the shuffle and counting options apply
+	opts := []game.Option{game.WithRules(table), game.WithBankroll(*bankroll)}
+	var sessionOpts []game.Option
+	if *seed != 0 {
+		sessionOpts = append(sessionOpts, game.WithSeed(*seed))
+	}
+	if *secure {
+		sessionOpts = append(sessionOpts, game.WithSecureShuffle())
+	}
+	if counting {
+		sessionOpts = append(sessionOpts, game.WithCountingSystem(system))
+	}
+
+	var g *game.Game
+	var err error
+	if *resume != "" {
+		g, err = loadSession(*resume, sessionOpts...)
+	} else {
+		names := getPlayerNames()
+		g, err = game.NewGame(names[0], append(opts, sessionOpts...)...)
+		for _, name := range names[1:] {
+			if err == nil {
+				err = g.SitDown(name, *bankroll)
+			}
 		}
 	}
+	if err != nil {
+		fmt.Printf("Error creating game: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Show the rules in force, which for a resumed session are the ones it was saved with
+	table = g.Rules()
+	clearScreen()
+	fmt.Println(rules.DisplayAllRules(table))
+	fmt.Println(rules.DisplayHelp(rules.Actions{Double: true, Split: true, Surrender: table.Surrender != rules.NoSurrender}))
+	fmt.Println("\nPress Enter to start...")
+	bufio.NewReader(os.Stdin).ReadString('\n')
+
+	// Main game loop
+	lastBets := make(map[string]int)
+	extras := overlays{coach: *coach, odds: *showOdds, ev: *showEV, count: counting}
+	for playRound(g, lastBets, extras) && nextRound(g, *bankroll) {
+	}
 
+	offerSave(g)
 	fmt.Println("\nThanks for playing!")
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"blackjack/internal/game"
+)
+
+// defaultSaveFile is offered when the player saves without choosing a file name
+const defaultSaveFile = "blackjack-session.json"
+
+// saveSession writes the complete game state to a file
+func saveSession(path string, g *game.Game) error {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session: %v", err)
+	}
+	return nil
+}
+
+// loadSession reads a game saved by saveSession
+// The options (such as a seed) apply to future shuffles of the restored shoe
+func loadSession(path string, opts ...game.Option) (*game.Game, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %v", err)
+	}
+
+	var snapshot game.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %v", err)
+	}
+	return game.FromSnapshot(snapshot, opts...)
+}
+
+// offerSave asks whether to save the session before quitting and saves it if so
+func offerSave(g *game.Game) {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("\nSave this session before quitting? (y/n): ")
+	input, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(input)) != "y" {
+		return
+	}
+
+	fmt.Printf("File name [%s]: ", defaultSaveFile)
+	path, _ := reader.ReadString('\n')
+	path = strings.TrimSpace(path)
+	if path == "" {
+		path = defaultSaveFile
+	}
+
+	if err := saveSession(path, g); err != nil {
+		fmt.Printf("Error saving session: %v\n", err)
+		return
+	}
+	fmt.Printf("Session saved. Resume it with: --resume %s\n", path)
+}
@@ -0,0 +1,76 @@
+// Command sim plays BlackJack rounds headlessly and reports the house edge
+// and variance of a strategy under a set of table rules
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"blackjack/internal/count"
+	"blackjack/internal/deck"
+	"blackjack/internal/rules"
+	"blackjack/internal/sim"
+)
+
+func main() {
+	rounds := flag.Int("rounds", 1000000, "number of rounds to play")
+	workers := flag.Int("workers", runtime.NumCPU(), "games to play side by side (default: every CPU core)")
+	seed := flag.Int64("seed", 1, "seed for the first worker's shuffles; worker i uses seed+i")
+	name := flag.String("strategy", "basic", "strategy to play: "+strings.Join(sim.StrategyNames(), ", "))
+	countName := flag.String("count", "", "break the results down by true count under this system: "+strings.Join(count.SystemNames(), ", "))
+	decks := flag.Int("decks", 6, "number of decks in the shoe (1, 2, 4, 6 or 8)")
+	penetration := flag.Float64("penetration", deck.DefaultPenetration, "percentage of the shoe dealt before reshuffling")
+	h17 := flag.Bool("h17", false, "dealer hits soft 17 (default: dealer stands on soft 17)")
+	noPeek := flag.Bool("no-peek", false, "European no-hole-card game: the dealer takes a second card only after the players act")
+	noDAS := flag.Bool("no-das", false, "split hands may not be doubled")
+	table := rules.DefaultTableRules()
+	flag.TextVar(&table.BlackjackPayout, "payout", table.BlackjackPayout, "what a BlackJack pays: 3:2, 6:5 or 1:1")
+	flag.TextVar(&table.Double, "double", table.Double, "which hands may be doubled: any, 9-11 or 10-11")
+	flag.TextVar(&table.Surrender, "surrender", table.Surrender, "when a hand may be surrendered: none, late or early")
+	flag.IntVar(&table.MaxSplits, "max-splits", table.MaxSplits, "splits allowed per round")
+	flag.BoolVar(&table.ResplitAces, "rsa", table.ResplitAces, "split Aces may be split again")
+	flag.Parse()
+
+	table.Decks = *decks
+	table.Penetration = *penetration
+	table.DealerHitsSoft17 = *h17
+	table.DealerPeeks = !*noPeek
+	table.DoubleAfterSplit = !*noDAS
+
+	play, ok := sim.Strategies[*name]
+	if !ok {
+		fmt.Printf("Error: unknown strategy %q (choose from %s)\n", *name, strings.Join(sim.StrategyNames(), ", "))
+		os.Exit(1)
+	}
+
+	var system count.System
+	if *countName != "" {
+		if system, ok = count.Systems[*countName]; !ok {
+			fmt.Printf("Error: unknown counting system %q (choose from %s)\n", *countName, strings.Join(count.SystemNames(), ", "))
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("Simulating %d rounds of the %s strategy...\n", *rounds, *name)
+	start := time.Now()
+	result, err := sim.Run(sim.Config{Rules: table, Strategy: play, Rounds: *rounds, Workers: *workers, Seed: *seed, Count: system})
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Show the flat bet the simulator places rather than the table limits
+	table.MinBet, table.MaxBet = sim.Unit, sim.Unit
+	fmt.Println()
+	for _, section := range rules.GetGameRules(table) {
+		if section.Title == "Table Rules" {
+			fmt.Println(rules.DisplaySection(section))
+		}
+	}
+	fmt.Println(result)
+	fmt.Printf("\nFinished in %s\n", time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"blackjack/internal/count"
+	"blackjack/internal/deck"
+)
+
+// trueCountTolerance is how far a true count answer may be from the exact
+// figure, since counters estimate the decks remaining by eye
+const trueCountTolerance = 0.5
+
+// trainer runs card counting practice from a shoe of its own
+type trainer struct {
+	reader *bufio.Reader
+	system count.System
+	decks  int
+	speed  time.Duration // How long each card is shown while counting through a shoe
+	source rand.Source   // Shuffles the cards and picks when to ask for the count
+	rng    *rand.Rand
+	score  trainingScore
+}
+
+// trainingScore tracks how a trainer session went
+type trainingScore struct {
+	asked, correct int           // Counts asked for while dealing, and how many were right
+	answerTime     time.Duration // Time taken to answer them all
+	drills         []drillResult
+}
+
+// drillResult records one count down a deck
+type drillResult struct {
+	elapsed time.Duration
+	correct bool
+}
+
+// newTrainer returns a trainer for the counting system and shoe size
+// A seed of 0 deals different cards every session
+func newTrainer(system count.System, decks int, speed time.Duration, seed int64) *trainer {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	source := rand.NewSource(seed)
+	return &trainer{
+		reader: bufio.NewReader(os.Stdin),
+		system: system,
+		decks:  decks,
+		speed:  speed,
+		source: source,
+		rng:    rand.New(source),
+	}
+}
+
+// run offers the drills until the player finishes, then shows the session score
+func (t *trainer) run() {
+	for {
+		fmt.Printf("\nCard Counting Trainer (%s)\n", t.system.Name)
+		fmt.Printf("1) Count through a shoe of %s, one card every %s\n", plural(t.decks, "deck"), t.speed)
+		fmt.Println("2) Count down a deck against the clock")
+		fmt.Println("q) Finish and show your score")
+		fmt.Print("Choose: ")
+
+		switch t.readLine() {
+		case "1":
+			t.countShoe()
+		case "2":
+			t.countDownDeck()
+		case "q", "quit":
+			fmt.Println("\n" + t.score.summary(t.system))
+			return
+		default:
+			fmt.Println("Please choose 1, 2 or q.")
+		}
+	}
+}
+
+// readLine reads a line of input, trimmed and in lower case
+func (t *trainer) readLine() string {
+	input, _ := t.reader.ReadString('\n')
+	return strings.ToLower(strings.TrimSpace(input))
+}
+
+// shuffled returns a freshly shuffled shoe of the given number of decks
+func (t *trainer) shuffled(decks int) (*deck.Deck, error) {
+	shoe, err := deck.NewShoe(decks)
+	if err != nil {
+		return nil, err
+	}
+	shoe.SetSource(t.source)
+	shoe.Shuffle()
+	return shoe, nil
+}
+
+// countShoe deals through a shoe at the trainer's speed, stopping every few
+// cards to ask for the running or true count, until the cut card comes out
+func (t *trainer) countShoe() {
+	shoe, err := t.shuffled(t.decks)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	counter := count.NewCounter(t.system, t.decks)
+
+	fmt.Println("\nKeep the count as the cards are dealt. Answer q to stop.")
+	next := t.nextQuestion()
+	for !shoe.CutCardReached() {
+		card, err := shoe.DrawCard()
+		if err != nil {
+			break
+		}
+		counter.Observe(card)
+		fmt.Printf("\r%-6s", card.ShortString()) // Each card replaces the last
+		time.Sleep(t.speed)
+
+		if counter.Seen() == next {
+			if !t.ask(counter) {
+				return
+			}
+			next += t.nextQuestion()
+		}
+	}
+	fmt.Printf("\nThe cut card came out after %d cards: %s\n", counter.Seen(), counter)
+}
+
+// nextQuestion returns how many cards to deal before asking for the count again
+func (t *trainer) nextQuestion() int {
+	return 6 + t.rng.Intn(7)
+}
+
+// ask asks for the running count, or for a balanced system sometimes the true
+// count, and scores the answer. It reports whether the player wants to go on.
+func (t *trainer) ask(counter *count.Counter) bool {
+	askTrue := t.system.Balanced() && t.rng.Intn(2) == 0
+	question := "Running count"
+	if askTrue {
+		question = "True count"
+	}
+
+	start := time.Now()
+	var answer float64
+	for {
+		fmt.Printf("\n%s? ", question)
+		input := t.readLine()
+		if input == "q" || input == "quit" {
+			return false
+		}
+		var err error
+		if answer, err = strconv.ParseFloat(input, 64); err == nil {
+			break
+		}
+		fmt.Println("Please enter a number (e.g., -3 or 1.5).")
+	}
+	t.score.asked++
+	t.score.answerTime += time.Since(start)
+
+	exact := counter.Running()
+	if askTrue {
+		exact = counter.True()
+	}
+	switch {
+	case gradeCount(answer, exact, askTrue):
+		t.score.correct++
+		fmt.Println("Correct!")
+	case askTrue:
+		fmt.Printf("Not quite: the true count is %+.1f (running count %+g over %.1f decks left)\n",
+			counter.True(), counter.Running(), counter.DecksRemaining())
+	default:
+		fmt.Printf("Not quite: the running count is %+g\n", counter.Running())
+	}
+	return true
+}
+
+// gradeCount reports whether an answer matches the exact count. A running
+// count must be exact, while a true count may be off by trueCountTolerance.
+func gradeCount(answer, exact float64, trueCount bool) bool {
+	if trueCount {
+		return math.Abs(answer-exact) <= trueCountTolerance
+	}
+	return answer == exact
+}
+
+// countDownDeck times the player turning over a single deck as fast as they can
+// The last card stays face down, so the count given at the end shows what it must be
+func (t *trainer) countDownDeck() {
+	cards, err := t.shuffled(1)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	counter := count.NewCounter(t.system, 1)
+
+	fmt.Println("\nPress Enter to turn each card, as fast as you can keep the count.")
+	fmt.Println("The last card stays face down: give the count when the others are done. Answer q to stop.")
+	fmt.Print("Press Enter to start the clock...")
+	if t.readLine() == "q" {
+		return
+	}
+
+	start := time.Now()
+	for cards.RemainingCards() > 1 {
+		card, _ := cards.DrawCard()
+		counter.Observe(card)
+		fmt.Printf("%2d/52  %-4s", counter.Seen(), card.ShortString())
+		if input := t.readLine(); input == "q" || input == "quit" {
+			return
+		}
+	}
+
+	var answer float64
+	for {
+		fmt.Print("Count? ")
+		input := t.readLine()
+		if input == "q" || input == "quit" {
+			return
+		}
+		if answer, err = strconv.ParseFloat(input, 64); err == nil {
+			break
+		}
+		fmt.Println("Please enter a number (e.g., -3 or 1.5).")
+	}
+	result := drillResult{elapsed: time.Since(start), correct: gradeCount(answer, counter.Running(), false)}
+	t.score.drills = append(t.score.drills, result)
+
+	if result.correct {
+		fmt.Println("Correct!")
+	} else {
+		fmt.Printf("Not quite: the count was %+g\n", counter.Running())
+	}
+	last, _ := cards.DrawCard()
+	counter.Observe(last)
+	fmt.Printf("The last card was %s, bringing the count to %+g.\n", last.ShortString(), counter.Running())
+	fmt.Printf("Time: %.1fs for the deck (%.2fs per card)\n", result.elapsed.Seconds(), result.elapsed.Seconds()/52)
+}
+
+// summary reports the session's accuracy and speed
+func (s trainingScore) summary(system count.System) string {
+	lines := []string{fmt.Sprintf("Counting Trainer Score (%s)", system.Name)}
+	if s.asked > 0 {
+		lines = append(lines, fmt.Sprintf("Counts asked: %d, correct: %d (%.0f%%), average answer time: %.1fs",
+			s.asked, s.correct, 100*float64(s.correct)/float64(s.asked), (s.answerTime/time.Duration(s.asked)).Seconds()))
+	}
+	if len(s.drills) > 0 {
+		correct, best := bestDrill(s.drills)
+		line := fmt.Sprintf("Decks counted down: %d, correct: %d", len(s.drills), correct)
+		if correct > 0 {
+			line += fmt.Sprintf(", best correct time: %.1fs (%.2fs per card)", best.Seconds(), best.Seconds()/52)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 1 {
+		lines = append(lines, "No counts checked this session.")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// bestDrill counts the drills answered correctly and returns the fastest of them
+// Wrong answers never set the best time, however quick they were.
+func bestDrill(drills []drillResult) (correct int, best time.Duration) {
+	for _, drill := range drills {
+		if !drill.correct {
+			continue
+		}
+		correct++
+		if best == 0 || drill.elapsed < best {
+			best = drill.elapsed
+		}
+	}
+	return correct, best
+}
+
+// plural formats a count with a noun, adding an "s" unless the count is one
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
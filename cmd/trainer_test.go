@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blackjack/internal/count"
+)
+
+// TestGradeCount tests how close a counting answer must be
+func TestGradeCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		answer    float64
+		exact     float64
+		trueCount bool
+		correct   bool
+	}{
+		{"Exact running count", -3, -3, false, true},
+		{"Running count off by a half", 2.5, 3, false, false},
+		{"Running count in halves", 1.5, 1.5, false, true},
+		{"Exact true count", 2, 2, true, true},
+		{"True count within tolerance", 1.5, 1.9, true, true},
+		{"True count at the tolerance", -1, -1.5, true, true},
+		{"True count outside tolerance", 1, 1.6, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := gradeCount(test.answer, test.exact, test.trueCount); got != test.correct {
+				t.Errorf("Expected %v for %v against %v, got %v", test.correct, test.answer, test.exact, got)
+			}
+		})
+	}
+}
+
+// TestBestDrill tests that only correct drills count towards the best time
+func TestBestDrill(t *testing.T) {
+	drills := []drillResult{
+		{elapsed: 40 * time.Second, correct: true},
+		{elapsed: 20 * time.Second, correct: false},
+		{elapsed: 30 * time.Second, correct: true},
+	}
+	if correct, best := bestDrill(drills); correct != 2 || best != 30*time.Second {
+		t.Errorf("Expected 2 correct with a best of 30s, got %d and %s", correct, best)
+	}
+	if correct, best := bestDrill(drills[1:2]); correct != 0 || best != 0 {
+		t.Errorf("Expected no correct drills and no best time, got %d and %s", correct, best)
+	}
+}
+
+// TestTrainingScoreSummary tests the session report
+func TestTrainingScoreSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		score  trainingScore
+		want   []string
+		absent string // Text the summary must leave out
+	}{
+		{"Nothing checked", trainingScore{}, []string{"Counting Trainer Score (Hi-Lo)", "No counts checked this session."}, "Counts asked"},
+		{"Counts asked", trainingScore{asked: 4, correct: 3, answerTime: 10 * time.Second},
+			[]string{"Counts asked: 4, correct: 3 (75%), average answer time: 2.5s"}, "Decks counted down"},
+		{"Decks counted down", trainingScore{drills: []drillResult{{26 * time.Second, true}, {20 * time.Second, false}}},
+			[]string{"Decks counted down: 2, correct: 1, best correct time: 26.0s (0.50s per card)"}, "No counts checked"},
+		{"No correct drills", trainingScore{drills: []drillResult{{20 * time.Second, false}}},
+			[]string{"Decks counted down: 1, correct: 0"}, "best correct time"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			summary := test.score.summary(count.HiLo)
+			for _, want := range test.want {
+				if !strings.Contains(summary, want) {
+					t.Errorf("Expected %q in:\n%s", want, summary)
+				}
+			}
+			if strings.Contains(summary, test.absent) {
+				t.Errorf("Expected no %q in:\n%s", test.absent, summary)
+			}
+		})
+	}
+}
+
+// TestPlural tests counting nouns
+func TestPlural(t *testing.T) {
+	if plural(1, "deck") != "1 deck" || plural(6, "deck") != "6 decks" {
+		t.Errorf("Unexpected plurals %q and %q", plural(1, "deck"), plural(6, "deck"))
+	}
+}
@@ -0,0 +1,152 @@
+// Package count keeps card counts for BlackJack under the common counting systems
+package count
+
+import (
+	"blackjack/internal/deck"
+	"fmt"
+	"sort"
+)
+
+// ace is the index of Aces in a system's tags, matching their card value
+const ace = 11
+
+// System is a card counting system: a tag added to the count for each card
+// value seen, from 2 to 11 (the Ace). Tens, Jacks, Queens and Kings share a tag.
+type System struct {
+	Name string
+	Tags [12]float64
+}
+
+// tags builds a system's tags from twos up to tens, then Aces
+func tags(twoToTen [9]float64, aces float64) [12]float64 {
+	var t [12]float64
+	copy(t[2:], twoToTen[:])
+	t[ace] = aces
+	return t
+}
+
+var (
+	// HiLo is the most widely used system: low cards count +1, tens and Aces -1
+	HiLo = System{"Hi-Lo", tags([9]float64{1, 1, 1, 1, 1, 0, 0, 0, -1}, -1)}
+
+	// KO (Knock-Out) also counts sevens, so it is unbalanced and needs no true count
+	KO = System{"KO", tags([9]float64{1, 1, 1, 1, 1, 1, 0, 0, -1}, -1)}
+
+	// OmegaII is a level-two system that leaves Aces out of the count
+	OmegaII = System{"Omega II", tags([9]float64{1, 1, 2, 2, 2, 1, 0, -1, -2}, 0)}
+
+	// Zen is a level-two system that counts Aces at half the weight of tens
+	Zen = System{"Zen", tags([9]float64{1, 1, 2, 2, 2, 1, 0, 0, -2}, -1)}
+
+	// WongHalves is a level-three system that counts in halves
+	WongHalves = System{"Wong Halves", tags([9]float64{0.5, 1, 1, 1.5, 1, 0.5, 0, -0.5, -1}, -1)}
+)
+
+// Systems holds the available counting systems, by the name used on the command line
+var Systems = map[string]System{
+	"hi-lo":       HiLo,
+	"ko":          KO,
+	"omega-ii":    OmegaII,
+	"zen":         Zen,
+	"wong-halves": WongHalves,
+}
+
+// SystemNames returns the command line names of the available systems in order
+func SystemNames() []string {
+	names := make([]string, 0, len(Systems))
+	for name := range Systems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Tag returns the system's count for a card
+func (s System) Tag(card deck.Card) float64 {
+	return s.Tags[card.Value()]
+}
+
+// deckTotal returns the count of a whole 52-card deck
+func (s System) deckTotal() float64 {
+	total := 0.0
+	for value := 2; value <= ace; value++ {
+		cards := 4.0
+		if value == 10 {
+			cards = 16 // Tens, Jacks, Queens and Kings
+		}
+		total += cards * s.Tags[value]
+	}
+	return total
+}
+
+// Balanced reports whether a whole deck counts to zero
+func (s System) Balanced() bool {
+	return s.deckTotal() == 0
+}
+
+// InitialCount returns the running count at the start of a shoe: zero for a
+// balanced system, or low enough that an unbalanced one reaches its pivot of
+// +4 with a single deck left (e.g., -20 for KO with six decks)
+func (s System) InitialCount(decks int) float64 {
+	if s.Balanced() {
+		return 0
+	}
+	return -s.deckTotal() * float64(decks-1)
+}
+
+// minDecksRemaining is the fewest decks the true count ever divides by,
+// so the last few cards of a shoe don't inflate it
+const minDecksRemaining = 0.5
+
+// Counter keeps the running count of the cards seen from a shoe
+type Counter struct {
+	System  System
+	decks   int     // Decks in the shoe being counted
+	running float64 // Running count, including the system's initial count
+	seen    int     // Cards seen since the shoe was shuffled
+}
+
+// NewCounter returns a counter for a freshly shuffled shoe of the given number of decks
+func NewCounter(system System, decks int) *Counter {
+	c := &Counter{System: system, decks: decks}
+	c.Reset()
+	return c
+}
+
+// Reset starts the count again for a freshly shuffled shoe
+func (c *Counter) Reset() {
+	c.running = c.System.InitialCount(c.decks)
+	c.seen = 0
+}
+
+// Observe counts a card as it is seen
+func (c *Counter) Observe(card deck.Card) {
+	c.running += c.System.Tag(card)
+	c.seen++
+}
+
+// Running returns the running count
+func (c *Counter) Running() float64 {
+	return c.running
+}
+
+// Seen returns the number of cards seen since the shoe was shuffled
+func (c *Counter) Seen() int {
+	return c.seen
+}
+
+// DecksRemaining returns how many decks are left to be seen, never less than half a deck
+func (c *Counter) DecksRemaining() float64 {
+	return max(float64(52*c.decks-c.seen)/52, minDecksRemaining)
+}
+
+// True returns the running count per deck remaining
+// Unbalanced systems such as KO are meant to be played from the running count alone
+func (c *Counter) True() float64 {
+	return c.running / c.DecksRemaining()
+}
+
+// String reports both counts (e.g., "Hi-Lo running count +6, true count +2.0")
+func (c *Counter) String() string {
+	return fmt.Sprintf("%s running count %+g, true count %+.1f", c.System.Name, c.running, c.True())
+}
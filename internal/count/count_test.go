@@ -0,0 +1,116 @@
+package count
+
+import (
+	"blackjack/internal/deck"
+	"testing"
+)
+
+// countShoe observes every card of a shoe with the system and returns the counter
+func countShoe(t *testing.T, system System, decks int) *Counter {
+	t.Helper()
+	shoe, err := deck.NewShoe(decks)
+	if err != nil {
+		t.Fatalf("Failed to create shoe: %v", err)
+	}
+	counter := NewCounter(system, decks)
+	for shoe.RemainingCards() > 0 {
+		card, _ := shoe.DrawCard()
+		counter.Observe(card)
+	}
+	return counter
+}
+
+// TestSystems tests that each system counts a whole shoe back to where it should end
+func TestSystems(t *testing.T) {
+	tests := []struct {
+		name     string
+		balanced bool
+		initial  float64 // Initial count for six decks
+	}{
+		{"hi-lo", true, 0},
+		{"ko", false, -20},
+		{"omega-ii", true, 0},
+		{"zen", true, 0},
+		{"wong-halves", true, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			system, ok := Systems[test.name]
+			if !ok {
+				t.Fatalf("Expected a system called %q", test.name)
+			}
+			if system.Balanced() != test.balanced {
+				t.Errorf("Expected balanced to be %v", test.balanced)
+			}
+			if got := system.InitialCount(6); got != test.initial {
+				t.Errorf("Expected an initial count of %g, got %g", test.initial, got)
+			}
+
+			// A balanced count ends at zero; KO ends at its pivot of +4 past where it started
+			counter := countShoe(t, system, 6)
+			if expected := test.initial + system.deckTotal()*6; counter.Running() != expected {
+				t.Errorf("Expected the count of a whole shoe to be %g, got %g", expected, counter.Running())
+			}
+			if counter.Seen() != 312 {
+				t.Errorf("Expected 312 cards seen, got %d", counter.Seen())
+			}
+		})
+	}
+
+	if names := SystemNames(); len(names) != len(Systems) || names[0] != "hi-lo" {
+		t.Errorf("Unexpected system names: %v", names)
+	}
+}
+
+// TestTags tests the tags of a few cards
+func TestTags(t *testing.T) {
+	tests := []struct {
+		system   System
+		rank     deck.Rank
+		expected float64
+	}{
+		{HiLo, deck.Six, 1},
+		{HiLo, deck.King, -1},
+		{KO, deck.Seven, 1},
+		{OmegaII, deck.Ace, 0},
+		{OmegaII, deck.Nine, -1},
+		{Zen, deck.Ace, -1},
+		{Zen, deck.Queen, -2},
+		{WongHalves, deck.Five, 1.5},
+		{WongHalves, deck.Nine, -0.5},
+	}
+	for _, test := range tests {
+		if got := test.system.Tag(deck.Card{Suit: deck.Hearts, Rank: test.rank}); got != test.expected {
+			t.Errorf("%s, %s: expected %g, got %g", test.system.Name, test.rank, test.expected, got)
+		}
+	}
+}
+
+// TestTrueCount tests converting the running count per deck remaining
+func TestTrueCount(t *testing.T) {
+	counter := NewCounter(HiLo, 2)
+	low := deck.Card{Suit: deck.Hearts, Rank: deck.Five}
+	for i := 0; i < 52; i++ {
+		counter.Observe(low) // +52 with one deck left
+	}
+	if counter.True() != 52 || counter.DecksRemaining() != 1 {
+		t.Errorf("Expected a true count of 52 with one deck left, got %g over %g decks", counter.True(), counter.DecksRemaining())
+	}
+	if got := counter.String(); got != "Hi-Lo running count +52, true count +52.0" {
+		t.Errorf("Unexpected text %q", got)
+	}
+
+	// Near the end of the shoe the count is never divided by less than half a deck
+	for i := 0; i < 50; i++ {
+		counter.Observe(low)
+	}
+	if counter.DecksRemaining() != minDecksRemaining {
+		t.Errorf("Expected at least half a deck remaining, got %g", counter.DecksRemaining())
+	}
+
+	counter.Reset()
+	if counter.Running() != 0 || counter.Seen() != 0 {
+		t.Errorf("Expected a fresh count after a reset, got %v", counter)
+	}
+}
@@ -8,61 +8,192 @@ import (
 )
 
 // Deck represents a collection of playing cards
+// A Deck may hold several standard decks combined into a shoe (see NewShoe)
 type Deck struct {
-	cards []Card // Using a slice to store cards
+	cards       []Card        // Using a slice to store cards
+	decks       int           // Number of standard 52-card decks in the shoe
+	preset      []Card        // Fixed card order for a stacked deck (see NewStackedDeck)
+	dealt       map[Rank]int  // Cards of each rank dealt since the last reset
+	remaining   map[Rank]int  // Cards of each rank still in the deck
+	cutCard     int           // Number of cards dealt before the cut card comes out (0 means no cut card)
+	penetration float64       // Cut card position as a percentage of the shoe, as it was set
+	rng         *rand.Rand    // Random number generator used by Shuffle
+	secure      bool          // Whether Shuffle uses crypto/rand (see SetSecure)
+	proof       *ShuffleProof // Proof for the current shoe, if it was shuffled securely
+	previous    *ShuffleProof // Proof for the shoe before it, safe to reveal
 }
 
+// DefaultPenetration is the cut card position used by new decks and shoes, as a percentage of the shoe
+const DefaultPenetration = 75.0
+
+// Suits lists all four suits in the order they are added to a new deck
+var Suits = []Suit{Hearts, Diamonds, Clubs, Spades}
+
+// Ranks lists all thirteen ranks in the order they are added to a new deck
+var Ranks = []Rank{Ace, Two, Three, Four, Five, Six, Seven, Eight, Nine, Ten, Jack, Queen, King}
+
+// validDeckCounts holds the shoe sizes supported by NewShoe
+var validDeckCounts = map[int]bool{1: true, 2: true, 4: true, 6: true, 8: true}
+
 // NewDeck creates a new standard deck of 52 playing cards
 func NewDeck() *Deck {
-	// Create an empty deck
+	d := &Deck{decks: 1}
+	d.Reset()
+	d.SetPenetration(DefaultPenetration) // Cannot fail for the default value
+	return d
+}
+
+// ValidDeckCount reports whether a shoe can be built from the given number of decks
+func ValidDeckCount(decks int) bool {
+	return validDeckCounts[decks]
+}
+
+// NewShoe creates a shoe of 1, 2, 4, 6 or 8 standard decks combined
+// The cards are in order; call Shuffle before dealing
+func NewShoe(decks int) (*Deck, error) {
+	if !validDeckCounts[decks] {
+		return nil, fmt.Errorf("invalid number of decks: %d (must be 1, 2, 4, 6 or 8)", decks)
+	}
+
+	d := &Deck{decks: decks}
+	d.Reset()
+	d.SetPenetration(DefaultPenetration) // Cannot fail for the default value
+	return d, nil
+}
+
+// SetPenetration places the cut card after the given percentage of the shoe (e.g. 75)
+// A penetration of 0 removes the cut card, so the deck is dealt until it runs out
+func (d *Deck) SetPenetration(percent float64) error {
+	if percent < 0 || percent >= 100 {
+		return fmt.Errorf("invalid penetration: %v%% (must be between 0 and 100)", percent)
+	}
+	if d.TotalCards() == 0 {
+		return nil // An empty stacked deck has nowhere to place the cut card
+	}
+
+	d.cutCard = int(float64(d.TotalCards()) * percent / 100)
+	d.penetration = percent
+	return nil
+}
+
+// Penetration returns the cut card position as a percentage of the shoe
+func (d *Deck) Penetration() float64 {
+	return d.penetration
+}
+
+// CutCardReached reports whether the cut card has come out, meaning the
+// deck should be reshuffled at the end of the current round
+func (d *Deck) CutCardReached() bool {
+	return d.cutCard > 0 && d.DealtCards() >= d.cutCard
+}
+
+// NewStackedDeck creates a deck that deals exactly the given cards, in order
+// Stacked decks are never shuffled and have no cut card, which makes them
+// useful for scripting scenarios in tests
+func NewStackedDeck(cards []Card) (*Deck, error) {
+	for _, card := range cards {
+		if _, err := NewCard(card.Suit, card.Rank); err != nil {
+			return nil, fmt.Errorf("invalid card in stacked deck: %v", err)
+		}
+	}
+
 	d := &Deck{
-		cards: make([]Card, 0, 52), // Initialize with 0 length but capacity of 52
+		decks:  (len(cards) + 51) / 52, // Number of decks needed to hold the cards
+		preset: append([]Card(nil), cards...),
 	}
+	d.Reset()
+	return d, nil
+}
 
-	// Define all suits and ranks
-	suits := []Suit{Hearts, Diamonds, Clubs, Spades}
-	ranks := []Rank{Ace, Two, Three, Four, Five, Six, Seven, Eight, Nine, Ten, Jack, Queen, King}
+// Stacked reports whether the deck was built in a fixed order by NewStackedDeck
+func (d *Deck) Stacked() bool {
+	return d.preset != nil
+}
 
-	// Add all combinations of suits and ranks
-	for _, suit := range suits {
-		for _, rank := range ranks {
-			// Create a new card and add it to the deck
-			card, _ := NewCard(suit, rank) // We can ignore the error here because we know these are valid
-			d.cards = append(d.cards, card)
+// Reset puts every card back into the deck in order and clears the dealt counts
+// A stacked deck goes back to its original preset order
+func (d *Deck) Reset() {
+	d.dealt = make(map[Rank]int)
+	d.remaining = make(map[Rank]int)
+
+	if d.preset != nil {
+		d.cards = append(make([]Card, 0, len(d.preset)), d.preset...)
+		for _, card := range d.cards {
+			d.remaining[card.Rank]++
 		}
+		return
 	}
 
-	return d
+	// Initialize with 0 length but enough capacity for every deck in the shoe
+	d.cards = make([]Card, 0, 52*d.decks)
+
+	// Add all combinations of suits and ranks, once per deck
+	for i := 0; i < d.decks; i++ {
+		for _, suit := range Suits {
+			for _, rank := range Ranks {
+				// Create a new card and add it to the deck
+				card, _ := NewCard(suit, rank) // We can ignore the error here because we know these are valid
+				d.cards = append(d.cards, card)
+				d.remaining[rank]++
+			}
+		}
+	}
+}
+
+// SetSource makes Shuffle draw its randomness from src
+// Two decks given sources with the same seed are always shuffled into the same order
+func (d *Deck) SetSource(src rand.Source) {
+	d.rng = rand.New(src)
+}
+
+// SetSeed makes Shuffle reproducible by seeding it with the given value
+func (d *Deck) SetSeed(seed int64) {
+	d.SetSource(rand.NewSource(seed))
 }
 
 // Shuffle randomizes the order of cards in the deck
 // Makes sure that the deck is shuffled before drawing cards
+// Stacked decks keep their preset order
 func (d *Deck) Shuffle() {
-	// Create a new random source with current time as seed to ensure randomness
-	source := rand.NewSource(time.Now().UnixNano())
-	r := rand.New(source)
+	if d.Stacked() {
+		return
+	}
+
+	if d.secure {
+		d.secureShuffle()
+		return
+	}
+
+	if d.rng == nil {
+		// No source was set, so use the current time as seed to ensure randomness
+		d.SetSeed(time.Now().UnixNano())
+	}
 
 	// Use the Fisher-Yates shuffle algorithm to randomize the order of cards
 	for i := len(d.cards) - 1; i > 0; i-- {
 		// Generate a random index between 0 and i
-		j := r.Intn(i + 1)
+		j := d.rng.Intn(i + 1)
 		// Swap cards[i] with cards[j]
 		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
 	}
 }
 
-// DrawCard removes and returns the top card from the deck 
+// DrawCard removes and returns the top card from the deck
 // In Go, we can return multiple values - here we return both the card and an error (if the deck is empty)
 func (d *Deck) DrawCard() (Card, error) {
-	// Check if deck is empty 
+	// Check if deck is empty
 	if len(d.cards) == 0 {
 		return Card{}, fmt.Errorf("cannot draw from empty deck")
 	}
 
-	// Get the top card 
+	// Get the top card
 	card := d.cards[0]
 	// Remove it from the deck (slice from index 1 onwards)
 	d.cards = d.cards[1:]
+
+	// Keep the per-rank counts in step with the cards
+	d.dealt[card.Rank]++
+	d.remaining[card.Rank]--
 	return card, nil
 }
 
@@ -71,6 +202,34 @@ func (d *Deck) RemainingCards() int {
 	return len(d.cards)
 }
 
+// DeckCount returns the number of standard decks the shoe was built from
+func (d *Deck) DeckCount() int {
+	return d.decks
+}
+
+// TotalCards returns the number of cards in the full shoe
+func (d *Deck) TotalCards() int {
+	if d.preset != nil {
+		return len(d.preset)
+	}
+	return 52 * d.decks
+}
+
+// DealtCards returns the number of cards dealt since the last reset
+func (d *Deck) DealtCards() int {
+	return d.TotalCards() - len(d.cards)
+}
+
+// Dealt returns how many cards of the given rank have been dealt since the last reset
+func (d *Deck) Dealt(rank Rank) int {
+	return d.dealt[rank]
+}
+
+// Remaining returns how many cards of the given rank are left in the deck
+func (d *Deck) Remaining(rank Rank) int {
+	return d.remaining[rank]
+}
+
 // String returns a string representation of the deck
 func (d *Deck) String() string {
 	// Create a string builder for efficient string concatenation
@@ -1,6 +1,7 @@
 package deck
 
 import (
+	"math/rand"
 	"strings"
 	"testing"
 )
@@ -241,3 +242,181 @@ func TestString(t *testing.T) {
 		}
 	})
 }
+
+// TestNewShoe tests building shoes from several decks
+func TestNewShoe(t *testing.T) {
+	for _, decks := range []int{1, 2, 4, 6, 8} {
+		shoe, err := NewShoe(decks)
+		if err != nil {
+			t.Fatalf("Unexpected error creating %d-deck shoe: %v", decks, err)
+		}
+		if shoe.DeckCount() != decks {
+			t.Errorf("Expected deck count %d, got %d", decks, shoe.DeckCount())
+		}
+		if shoe.RemainingCards() != 52*decks {
+			t.Errorf("Expected %d cards, got %d", 52*decks, shoe.RemainingCards())
+		}
+		for _, rank := range Ranks {
+			if shoe.Remaining(rank) != 4*decks {
+				t.Errorf("Expected %d cards of rank %s, got %d", 4*decks, rank, shoe.Remaining(rank))
+			}
+		}
+	}
+
+	for _, decks := range []int{0, 3, 5, 7, 9, -1} {
+		if _, err := NewShoe(decks); err == nil {
+			t.Errorf("Expected error creating %d-deck shoe", decks)
+		}
+	}
+}
+
+// TestShoeRankTracking tests the dealt and remaining counts per rank
+func TestShoeRankTracking(t *testing.T) {
+	shoe, _ := NewShoe(6)
+	shoe.Shuffle()
+
+	dealt := make(map[Rank]int)
+	for i := 0; i < 100; i++ {
+		card, err := shoe.DrawCard()
+		if err != nil {
+			t.Fatalf("Unexpected error drawing card: %v", err)
+		}
+		dealt[card.Rank]++
+	}
+
+	for _, rank := range Ranks {
+		if shoe.Dealt(rank) != dealt[rank] {
+			t.Errorf("Expected %d %s dealt, got %d", dealt[rank], rank, shoe.Dealt(rank))
+		}
+		if shoe.Remaining(rank) != 24-dealt[rank] {
+			t.Errorf("Expected %d %s remaining, got %d", 24-dealt[rank], rank, shoe.Remaining(rank))
+		}
+	}
+	if shoe.DealtCards() != 100 {
+		t.Errorf("Expected 100 cards dealt, got %d", shoe.DealtCards())
+	}
+
+	// Reset should bring the shoe back to full
+	shoe.Reset()
+	if shoe.RemainingCards() != 312 || shoe.DealtCards() != 0 {
+		t.Errorf("Expected full shoe after reset, got %d remaining and %d dealt", shoe.RemainingCards(), shoe.DealtCards())
+	}
+	if shoe.Dealt(Ace) != 0 || shoe.Remaining(Ace) != 24 {
+		t.Error("Expected rank counts to be cleared after reset")
+	}
+}
+
+// TestPenetration tests the cut card position
+func TestPenetration(t *testing.T) {
+	shoe, _ := NewShoe(6)
+	if shoe.Penetration() != DefaultPenetration {
+		t.Errorf("Expected default penetration %v, got %v", DefaultPenetration, shoe.Penetration())
+	}
+
+	if err := shoe.SetPenetration(50); err != nil {
+		t.Fatalf("Unexpected error setting penetration: %v", err)
+	}
+	for i := 0; i < 155; i++ {
+		shoe.DrawCard()
+	}
+	if shoe.CutCardReached() {
+		t.Error("Cut card should not be out after 155 of 312 cards")
+	}
+	shoe.DrawCard()
+	if !shoe.CutCardReached() {
+		t.Error("Cut card should be out after 156 of 312 cards")
+	}
+
+	// Reset puts the cut card back in the same place
+	shoe.Reset()
+	if shoe.CutCardReached() || shoe.Penetration() != 50 {
+		t.Error("Expected cut card to be kept in place after reset")
+	}
+
+	// Without a cut card the deck is dealt to the end
+	if err := shoe.SetPenetration(0); err != nil {
+		t.Fatalf("Unexpected error removing cut card: %v", err)
+	}
+	for shoe.RemainingCards() > 0 {
+		shoe.DrawCard()
+	}
+	if shoe.CutCardReached() {
+		t.Error("Deck without a cut card should never reach it")
+	}
+
+	for _, pct := range []float64{-1, 100, 150} {
+		if err := shoe.SetPenetration(pct); err == nil {
+			t.Errorf("Expected error for penetration %v", pct)
+		}
+	}
+}
+
+// TestSetSeed tests that seeded shuffles are reproducible
+func TestSetSeed(t *testing.T) {
+	deck1, _ := NewShoe(2)
+	deck2, _ := NewShoe(2)
+	deck1.SetSeed(7)
+	deck2.SetSource(rand.NewSource(7))
+
+	// Compare two shuffles in a row, so the source is reused between them
+	for round := 0; round < 2; round++ {
+		deck1.Reset()
+		deck2.Reset()
+		deck1.Shuffle()
+		deck2.Shuffle()
+		for i := range deck1.cards {
+			if deck1.cards[i] != deck2.cards[i] {
+				t.Fatalf("Shuffle %d differs at card %d: %s vs %s", round+1, i, deck1.cards[i], deck2.cards[i])
+			}
+		}
+	}
+}
+
+// TestNewStackedDeck tests building a deck in a chosen order
+func TestNewStackedDeck(t *testing.T) {
+	cards := []Card{
+		{Suit: Spades, Rank: Ace},
+		{Suit: Hearts, Rank: King},
+		{Suit: Diamonds, Rank: Seven},
+		{Suit: Spades, Rank: Ace},
+	}
+	stacked, err := NewStackedDeck(cards)
+	if err != nil {
+		t.Fatalf("Unexpected error creating stacked deck: %v", err)
+	}
+	if !stacked.Stacked() {
+		t.Error("Expected deck to be stacked")
+	}
+	if stacked.TotalCards() != 4 || stacked.Remaining(Ace) != 2 {
+		t.Errorf("Expected 4 cards with 2 aces, got %d cards with %d aces", stacked.TotalCards(), stacked.Remaining(Ace))
+	}
+
+	// Shuffling keeps the preset order
+	stacked.Shuffle()
+	for i, want := range cards {
+		got, err := stacked.DrawCard()
+		if err != nil {
+			t.Fatalf("Unexpected error drawing card %d: %v", i+1, err)
+		}
+		if got != want {
+			t.Errorf("Card %d: expected %s, got %s", i+1, want, got)
+		}
+	}
+	if _, err := stacked.DrawCard(); err == nil {
+		t.Error("Expected error drawing past the end of a stacked deck")
+	}
+	if stacked.CutCardReached() {
+		t.Error("Stacked deck should not have a cut card")
+	}
+
+	// Reset restores the preset order, and changing the input has no effect
+	cards[0] = Card{Suit: Clubs, Rank: Two}
+	stacked.Reset()
+	if first, _ := stacked.DrawCard(); first != (Card{Suit: Spades, Rank: Ace}) {
+		t.Errorf("Expected Ace of Spades after reset, got %s", first)
+	}
+
+	if _, err := NewStackedDeck([]Card{{Suit: "Stars", Rank: Ace}}); err == nil {
+		t.Error("Expected error for invalid card")
+	}
+}
@@ -0,0 +1,104 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// MarshalText encodes the card as its two-character code (e.g., "AS")
+// This also makes cards appear as strings in JSON
+func (c Card) MarshalText() ([]byte, error) {
+	if _, err := NewCard(c.Suit, c.Rank); err != nil {
+		return nil, err
+	}
+	return []byte(c.Code()), nil
+}
+
+// UnmarshalText decodes a card written in any notation accepted by ParseCard
+func (c *Card) UnmarshalText(text []byte) error {
+	card, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
+
+// deckJSON is the serialized form of a Deck
+// The random source is not saved. A secure shoe is saved with its cards in
+// sorted order rather than dealing order, so the file cannot predict the deal.
+type deckJSON struct {
+	Decks       int           `json:"decks"`
+	Cards       []Card        `json:"cards"`            // Cards left to deal, top card first
+	Preset      []Card        `json:"preset,omitempty"` // Full order of a stacked deck
+	Penetration float64       `json:"penetration"`      // Cut card position as a percentage of the shoe
+	Secure      bool          `json:"secure,omitempty"`
+	Proof       *ShuffleProof `json:"proof,omitempty"` // Proof for the secure shoe retired when the deck was saved
+}
+
+// MarshalJSON saves the deck's remaining cards in dealing order, along with its settings
+// A secure shoe's remaining cards are first reshuffled, retiring the order
+// the house committed to: its proof is saved so it can still be checked,
+// while the new order stays secret and is replaced when the deck is restored.
+func (d *Deck) MarshalJSON() ([]byte, error) {
+	saved := deckJSON{
+		Decks:       d.decks,
+		Cards:       d.cards,
+		Preset:      d.preset,
+		Penetration: d.penetration,
+		Secure:      d.secure,
+	}
+	if d.secure && !d.Stacked() {
+		d.secureShuffle()
+		saved.Cards = append([]Card(nil), d.cards...)
+		sort.Slice(saved.Cards, func(i, j int) bool { return saved.Cards[i].Code() < saved.Cards[j].Code() })
+		saved.Proof = d.previous
+	}
+	return json.Marshal(saved)
+}
+
+// UnmarshalJSON restores a deck saved by MarshalJSON, including the dealt counts
+func (d *Deck) UnmarshalJSON(data []byte) error {
+	var saved deckJSON
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+
+	restored := &Deck{decks: saved.Decks, preset: saved.Preset, secure: saved.Secure}
+	if saved.Preset == nil && !validDeckCounts[saved.Decks] {
+		return fmt.Errorf("invalid number of decks: %d", saved.Decks)
+	}
+	if err := restored.SetPenetration(saved.Penetration); err != nil {
+		return err
+	}
+
+	// Start from the full shoe and take away every card that is no longer in it
+	restored.Reset()
+	left := make(map[Card]int)
+	for _, card := range restored.cards {
+		left[card]++
+	}
+	for _, card := range saved.Cards {
+		if left[card] == 0 {
+			return fmt.Errorf("deck holds more copies of %s than the shoe contains", card)
+		}
+		left[card]--
+	}
+	for card, count := range left {
+		restored.dealt[card.Rank] += count
+		restored.remaining[card.Rank] -= count
+	}
+	restored.cards = append(make([]Card, 0, restored.TotalCards()), saved.Cards...)
+
+	// A secure shoe was saved in sorted order, so it is shuffled afresh with a new
+	// commitment, keeping the proof for the shoe retired by the save
+	if restored.secure && !restored.Stacked() {
+		restored.secureShuffle()
+		restored.previous = saved.Proof
+	}
+
+	restored.rng = d.rng // Keep any random source already set on the receiver
+	*d = *restored
+	return nil
+}
@@ -0,0 +1,161 @@
+package deck
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+)
+
+// TestCardText tests text and JSON encoding of cards
+func TestCardText(t *testing.T) {
+	card := Card{Suit: Hearts, Rank: Ten}
+	text, err := card.MarshalText()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(text) != "TH" {
+		t.Errorf("Expected \"TH\", got %q", text)
+	}
+
+	data, err := json.Marshal([]Card{card, {Suit: Spades, Rank: Ace}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(data) != `["TH","AS"]` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	var cards []Card
+	if err := json.Unmarshal([]byte(`["TH","A♠","10d"]`), &cards); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cards) != 3 || cards[0] != card || cards[2] != (Card{Suit: Diamonds, Rank: Ten}) {
+		t.Errorf("Unexpected cards %v", cards)
+	}
+
+	if _, err := (Card{}).MarshalText(); err == nil {
+		t.Error("Expected error marshalling an invalid card")
+	}
+	if err := json.Unmarshal([]byte(`["XX"]`), &cards); err == nil {
+		t.Error("Expected error unmarshalling an invalid card")
+	}
+}
+
+// TestDeckJSON tests saving and restoring a deck
+func TestDeckJSON(t *testing.T) {
+	shoe, _ := NewShoe(2)
+	shoe.SetPenetration(60)
+	shoe.Shuffle()
+	for i := 0; i < 30; i++ {
+		shoe.DrawCard()
+	}
+
+	data, err := json.Marshal(shoe)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	restored := &Deck{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if restored.DeckCount() != 2 || restored.Penetration() != shoe.Penetration() {
+		t.Errorf("Expected 2 decks at %v%%, got %d at %v%%", shoe.Penetration(), restored.DeckCount(), restored.Penetration())
+	}
+	if restored.DealtCards() != 30 {
+		t.Errorf("Expected 30 dealt cards, got %d", restored.DealtCards())
+	}
+	for _, rank := range Ranks {
+		if restored.Dealt(rank) != shoe.Dealt(rank) || restored.Remaining(rank) != shoe.Remaining(rank) {
+			t.Errorf("Rank %s counts differ after restore", rank)
+		}
+	}
+	for shoe.RemainingCards() > 0 {
+		want, _ := shoe.DrawCard()
+		got, err := restored.DrawCard()
+		if err != nil || got != want {
+			t.Fatalf("Expected %s next, got %s (%v)", want, got, err)
+		}
+	}
+
+	t.Run("Stacked deck", func(t *testing.T) {
+		stacked, _ := NewStackedDeck([]Card{{Suit: Spades, Rank: Ace}, {Suit: Hearts, Rank: Two}})
+		stacked.DrawCard()
+		data, _ := json.Marshal(stacked)
+
+		restored := &Deck{}
+		if err := json.Unmarshal(data, restored); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !restored.Stacked() || restored.RemainingCards() != 1 || restored.Dealt(Ace) != 1 {
+			t.Error("Expected stacked deck with one card left and the ace dealt")
+		}
+	})
+
+	invalid := []string{
+		`{"decks":3,"cards":[]}`,
+		`{"decks":1,"cards":["AS","AS"]}`,
+		`{"decks":1,"cards":[],"penetration":120}`,
+		`{"decks":1,"cards":["ZZ"]}`,
+	}
+	for _, input := range invalid {
+		if err := json.Unmarshal([]byte(input), &Deck{}); err == nil {
+			t.Errorf("Expected error restoring %s", input)
+		}
+	}
+
+}
+
+// TestSecureDeckJSON tests that a saved secure shoe cannot be predicted from
+// the file, and that both the retired and the resumed shoes can be checked
+func TestSecureDeckJSON(t *testing.T) {
+	shoe, _ := NewShoe(2)
+	shoe.SetSecure(true)
+	shoe.Shuffle()
+	for i := 0; i < 20; i++ {
+		shoe.DrawCard()
+	}
+	committed := shoe.Commitment()
+
+	data, err := json.Marshal(shoe)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var saved deckJSON
+	json.Unmarshal(data, &saved)
+	if !sort.SliceIsSorted(saved.Cards, func(i, j int) bool { return saved.Cards[i].Code() < saved.Cards[j].Code() }) {
+		t.Error("Expected the saved cards in sorted order, not dealing order")
+	}
+	if shoe.Commitment() == committed {
+		t.Error("Expected saving to retire the committed order")
+	}
+
+	restored := &Deck{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !restored.Secure() || restored.RemainingCards() != 84 || restored.DealtCards() != 20 {
+		t.Errorf("Expected a secure shoe with 84 cards left, got %d", restored.RemainingCards())
+	}
+	for _, rank := range Ranks {
+		if restored.Remaining(rank) != shoe.Remaining(rank) {
+			t.Errorf("Rank %s counts differ after restore", rank)
+		}
+	}
+
+	proof, ok := restored.PreviousProof()
+	if !ok || proof.Commitment != committed || !proof.Verify() {
+		t.Errorf("Expected the retired shoe's proof to verify against %s, got %+v", committed, proof)
+	}
+	if restored.Commitment() == "" || restored.Commitment() == committed {
+		t.Error("Expected the resumed shoe to have a commitment of its own")
+	}
+
+	// The resumed shoe's own proof is revealed at its next shuffle
+	current := restored.Commitment()
+	restored.Reset()
+	restored.Shuffle()
+	if proof, ok := restored.PreviousProof(); !ok || proof.Commitment != current || !proof.Verify() {
+		t.Error("Expected the resumed shoe's proof to verify once it is replaced")
+	}
+}
@@ -0,0 +1,137 @@
+package deck
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// suitLetters maps suits to the single letter used in card codes
+var suitLetters = map[Suit]string{
+	Hearts:   "H",
+	Diamonds: "D",
+	Clubs:    "C",
+	Spades:   "S",
+}
+
+// rankLetters maps ranks to the single character used in card codes
+// Ten is written as "T" so that every code is exactly two characters
+var rankLetters = map[Rank]string{
+	Ace:   "A",
+	Two:   "2",
+	Three: "3",
+	Four:  "4",
+	Five:  "5",
+	Six:   "6",
+	Seven: "7",
+	Eight: "8",
+	Nine:  "9",
+	Ten:   "T",
+	Jack:  "J",
+	Queen: "Q",
+	King:  "K",
+}
+
+// parseSuits maps every accepted suit notation (letters and symbols) to a suit
+var parseSuits = map[string]Suit{
+	"H": Hearts, "♥": Hearts, "♡": Hearts,
+	"D": Diamonds, "♦": Diamonds, "♢": Diamonds,
+	"C": Clubs, "♣": Clubs, "♧": Clubs,
+	"S": Spades, "♠": Spades, "♤": Spades,
+}
+
+// parseRanks maps every accepted rank notation to a rank
+var parseRanks = map[string]Rank{
+	"A": Ace, "2": Two, "3": Three, "4": Four, "5": Five, "6": Six, "7": Seven,
+	"8": Eight, "9": Nine, "10": Ten, "T": Ten, "J": Jack, "Q": Queen, "K": King,
+}
+
+// Code returns the two-character ASCII code of the card (e.g., "AS", "TH")
+func (c Card) Code() string {
+	return rankLetters[c.Rank] + suitLetters[c.Suit]
+}
+
+// ParseCard parses a card written in any common notation:
+// codes ("AH", "Ah", "Th", "10h"), symbols ("A♥", "10♥") or long form ("Ace of Hearts")
+func ParseCard(s string) (Card, error) {
+	s = strings.TrimSpace(s)
+
+	// Long form, as produced by Card.String
+	if rank, suit, ok := strings.Cut(s, " of "); ok {
+		return NewCard(Suit(strings.TrimSpace(suit)), Rank(strings.TrimSpace(rank)))
+	}
+
+	// Short form: the suit is the last character, the rank is everything before it
+	runes := []rune(s)
+	if len(runes) < 2 {
+		return Card{}, fmt.Errorf("invalid card notation: %q", s)
+	}
+	suitPart := strings.ToUpper(string(runes[len(runes)-1]))
+	rankPart := strings.ToUpper(string(runes[:len(runes)-1]))
+
+	suit, ok := parseSuits[suitPart]
+	if !ok {
+		return Card{}, fmt.Errorf("invalid suit in card %q", s)
+	}
+	rank, ok := parseRanks[rankPart]
+	if !ok {
+		return Card{}, fmt.Errorf("invalid rank in card %q", s)
+	}
+
+	return Card{Suit: suit, Rank: rank}, nil
+}
+
+// ParseCards parses a list of cards separated by spaces and/or commas (e.g., "AS KH, 7d")
+func ParseCards(s string) ([]Card, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	cards := make([]Card, 0, len(fields))
+	for _, field := range fields {
+		card, err := ParseCard(field)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// FormatCards writes cards as space separated codes, which ParseCards reads back
+func FormatCards(cards []Card) string {
+	codes := make([]string, len(cards))
+	for i, card := range cards {
+		codes[i] = card.Code()
+	}
+	return strings.Join(codes, " ")
+}
+
+// Encode packs the card into a single byte: the suit in the high four bits
+// (0-3) and the rank in the low four bits (1-13). Invalid cards encode as 0.
+func (c Card) Encode() byte {
+	suit, rank := indexOf(Suits, c.Suit), indexOf(Ranks, c.Rank)
+	if suit < 0 || rank < 0 {
+		return 0
+	}
+	return byte(suit<<4 | (rank + 1))
+}
+
+// DecodeCard unpacks a card encoded by Card.Encode
+func DecodeCard(b byte) (Card, error) {
+	suit, rank := int(b>>4), int(b&0x0f)-1
+	if suit >= len(Suits) || rank < 0 || rank >= len(Ranks) {
+		return Card{}, fmt.Errorf("invalid card encoding: %#02x", b)
+	}
+	return Card{Suit: Suits[suit], Rank: Ranks[rank]}, nil
+}
+
+// indexOf returns the position of v in list, or -1 if it is not there
+func indexOf[T comparable](list []T, v T) int {
+	for i, item := range list {
+		if item == v {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,138 @@
+package deck
+
+import "testing"
+
+// TestParseCard tests parsing the common card notations
+func TestParseCard(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Card
+	}{
+		{"AH", Card{Suit: Hearts, Rank: Ace}},
+		{"Ah", Card{Suit: Hearts, Rank: Ace}},
+		{"ah", Card{Suit: Hearts, Rank: Ace}},
+		{"A♥", Card{Suit: Hearts, Rank: Ace}},
+		{"10h", Card{Suit: Hearts, Rank: Ten}},
+		{"Th", Card{Suit: Hearts, Rank: Ten}},
+		{"10♦", Card{Suit: Diamonds, Rank: Ten}},
+		{"kc", Card{Suit: Clubs, Rank: King}},
+		{"Q♣", Card{Suit: Clubs, Rank: Queen}},
+		{"JS", Card{Suit: Spades, Rank: Jack}},
+		{"2♠", Card{Suit: Spades, Rank: Two}},
+		{" 7d ", Card{Suit: Diamonds, Rank: Seven}},
+		{"Ace of Hearts", Card{Suit: Hearts, Rank: Ace}},
+		{"Ten of Spades", Card{Suit: Spades, Rank: Ten}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			card, err := ParseCard(test.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if card != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, card)
+			}
+		})
+	}
+
+	invalid := []string{"", "A", "1H", "11H", "AX", "ZH", "A♥♥", "Ace of Stars", "Eleven of Hearts"}
+	for _, input := range invalid {
+		if _, err := ParseCard(input); err == nil {
+			t.Errorf("Expected error parsing %q", input)
+		}
+	}
+}
+
+// TestParseCards tests parsing lists of cards
+func TestParseCards(t *testing.T) {
+	cards, err := ParseCards("AS KH, 7D,10c  Q♥")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []Card{
+		{Suit: Spades, Rank: Ace},
+		{Suit: Hearts, Rank: King},
+		{Suit: Diamonds, Rank: Seven},
+		{Suit: Clubs, Rank: Ten},
+		{Suit: Hearts, Rank: Queen},
+	}
+	if len(cards) != len(expected) {
+		t.Fatalf("Expected %d cards, got %d", len(expected), len(cards))
+	}
+	for i := range expected {
+		if cards[i] != expected[i] {
+			t.Errorf("Card %d: expected %s, got %s", i+1, expected[i], cards[i])
+		}
+	}
+
+	if cards, err := ParseCards("  "); err != nil || len(cards) != 0 {
+		t.Errorf("Expected no cards and no error for blank input, got %v, %v", cards, err)
+	}
+	if _, err := ParseCards("AS XX KH"); err == nil {
+		t.Error("Expected error for invalid card in list")
+	}
+}
+
+// TestCodeRoundTrip tests that every card survives Code, ShortString, String and FormatCards
+func TestCodeRoundTrip(t *testing.T) {
+	all := NewDeck().cards
+	for _, card := range all {
+		for _, s := range []string{card.Code(), card.ShortString(), card.String()} {
+			parsed, err := ParseCard(s)
+			if err != nil {
+				t.Errorf("Unexpected error parsing %q: %v", s, err)
+			} else if parsed != card {
+				t.Errorf("Parsing %q gave %s, expected %s", s, parsed, card)
+			}
+		}
+		if len(card.Code()) != 2 {
+			t.Errorf("Expected two-character code for %s, got %q", card, card.Code())
+		}
+	}
+
+	parsed, err := ParseCards(FormatCards(all))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing formatted deck: %v", err)
+	}
+	for i := range all {
+		if parsed[i] != all[i] {
+			t.Errorf("Card %d changed in round trip: %s vs %s", i+1, all[i], parsed[i])
+		}
+	}
+}
+
+// TestEncode tests the one-byte card encoding
+func TestEncode(t *testing.T) {
+	seen := make(map[byte]bool)
+	for _, card := range NewDeck().cards {
+		b := card.Encode()
+		if b == 0 {
+			t.Errorf("Valid card %s encoded as 0", card)
+		}
+		if seen[b] {
+			t.Errorf("Encoding %#02x used twice", b)
+		}
+		seen[b] = true
+
+		decoded, err := DecodeCard(b)
+		if err != nil {
+			t.Errorf("Unexpected error decoding %s: %v", card, err)
+		}
+		if decoded != card {
+			t.Errorf("Expected %s after decoding, got %s", card, decoded)
+		}
+	}
+
+	if (Card{Suit: Spades, Rank: Ace}).Encode() != 0x31 {
+		t.Error("Expected Ace of Spades to encode as 0x31")
+	}
+	if (Card{}).Encode() != 0 {
+		t.Error("Expected invalid card to encode as 0")
+	}
+	for _, b := range []byte{0x00, 0x0e, 0x40, 0xff} {
+		if _, err := DecodeCard(b); err == nil {
+			t.Errorf("Expected error decoding %#02x", b)
+		}
+	}
+}
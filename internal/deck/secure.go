@@ -0,0 +1,89 @@
+package deck
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ShuffleProof lets players check that a shoe was dealt in the order the
+// house committed to before the first card came out
+type ShuffleProof struct {
+	Commitment string `json:"commitment"` // SHA-256 of the salt and order, published before the deal
+	Salt       string `json:"salt"`       // Random salt, kept secret until the shoe is finished
+	Order      string `json:"order"`      // Card order right after the shuffle, kept secret until the shoe is finished
+}
+
+// Verify checks that the salt and order match the published commitment
+func (p ShuffleProof) Verify() bool {
+	return p.Commitment != "" && p.Commitment == commit(p.Salt, p.Order)
+}
+
+// commit hashes a salt and a card order into a commitment
+func commit(salt, order string) string {
+	sum := sha256.Sum256([]byte(salt + ":" + order))
+	return hex.EncodeToString(sum[:])
+}
+
+// SetSecure switches the deck to cryptographically secure shuffling
+// Secure shuffles use crypto/rand and publish a commitment to the new order
+func (d *Deck) SetSecure(secure bool) {
+	d.secure = secure
+}
+
+// Secure reports whether the deck uses cryptographically secure shuffling
+func (d *Deck) Secure() bool {
+	return d.secure
+}
+
+// Commitment returns the commitment for the current shoe, to publish before the deal
+// It is empty if the deck has not been given a secure shuffle
+func (d *Deck) Commitment() string {
+	if d.proof == nil {
+		return ""
+	}
+	return d.proof.Commitment
+}
+
+// PreviousProof returns the proof for the shoe before the last secure shuffle
+// It is only revealed once that shoe has been replaced, so it cannot help predict the cards
+func (d *Deck) PreviousProof() (ShuffleProof, bool) {
+	if d.previous == nil {
+		return ShuffleProof{}, false
+	}
+	return *d.previous, true
+}
+
+// secureShuffle shuffles the deck with crypto/rand and commits to the new order
+func (d *Deck) secureShuffle() {
+	// Fisher-Yates again, but with unbiased indexes from crypto/rand
+	for i := len(d.cards) - 1; i > 0; i-- {
+		j := secureIntn(i + 1)
+		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
+	}
+
+	// Record the order and a fresh salt, keeping the old proof so it can be revealed
+	salt := make([]byte, 16)
+	rand.Read(salt) // Never fails: crypto/rand.Read always fills the buffer
+	order := make([]string, len(d.cards))
+	for i, card := range d.cards {
+		order[i] = card.ShortString()
+	}
+
+	proof := &ShuffleProof{Salt: hex.EncodeToString(salt), Order: strings.Join(order, " ")}
+	proof.Commitment = commit(proof.Salt, proof.Order)
+	d.previous, d.proof = d.proof, proof
+}
+
+// secureIntn returns a uniformly distributed random number in [0, n)
+// crypto/rand.Int uses rejection sampling, so no index is more likely than another
+func secureIntn(n int) int {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic(fmt.Sprintf("secure shuffle: %v", err))
+	}
+	return int(v.Int64())
+}
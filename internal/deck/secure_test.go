@@ -0,0 +1,110 @@
+package deck
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSecureShuffle tests shuffling with crypto/rand
+func TestSecureShuffle(t *testing.T) {
+	shoe, _ := NewShoe(2)
+	shoe.SetSecure(true)
+	if !shoe.Secure() {
+		t.Fatal("Expected deck to be in secure mode")
+	}
+	if shoe.Commitment() != "" {
+		t.Error("Expected no commitment before the first shuffle")
+	}
+
+	shoe.Shuffle()
+	if len(shoe.cards) != 104 {
+		t.Fatalf("Expected 104 cards after shuffle, got %d", len(shoe.cards))
+	}
+
+	// Same cards, different order
+	fresh, _ := NewShoe(2)
+	counts := make(map[Card]int)
+	differences := 0
+	for i, card := range shoe.cards {
+		counts[card]++
+		if card != fresh.cards[i] {
+			differences++
+		}
+	}
+	for card, count := range counts {
+		if count != 2 {
+			t.Errorf("Expected 2 copies of %s, got %d", card, count)
+		}
+	}
+	if differences < 50 {
+		t.Errorf("Secure shuffle didn't appear to randomize the shoe. Only %d cards moved", differences)
+	}
+}
+
+// TestShuffleProof tests committing to and revealing a shuffled order
+func TestShuffleProof(t *testing.T) {
+	shoe := NewDeck()
+	shoe.SetSecure(true)
+	shoe.Shuffle()
+
+	commitment := shoe.Commitment()
+	if len(commitment) != 64 {
+		t.Fatalf("Expected a hex SHA-256 commitment, got %q", commitment)
+	}
+	if _, ok := shoe.PreviousProof(); ok {
+		t.Error("The current shoe's proof should not be revealed")
+	}
+
+	// Remember the dealt order, then finish the shoe
+	var dealt []string
+	for shoe.RemainingCards() > 0 {
+		card, _ := shoe.DrawCard()
+		dealt = append(dealt, card.ShortString())
+	}
+	shoe.Reset()
+	shoe.Shuffle()
+
+	proof, ok := shoe.PreviousProof()
+	if !ok {
+		t.Fatal("Expected the finished shoe's proof to be revealed")
+	}
+	if proof.Commitment != commitment {
+		t.Error("Revealed proof does not match the published commitment")
+	}
+	if proof.Order != strings.Join(dealt, " ") {
+		t.Error("Revealed order does not match the cards dealt")
+	}
+	if !proof.Verify() {
+		t.Error("Expected proof to verify")
+	}
+	if shoe.Commitment() == commitment {
+		t.Error("Expected a new commitment for the new shoe")
+	}
+
+	// Tampering with the order or salt breaks the proof
+	tampered := proof
+	tampered.Order = strings.Replace(proof.Order, " ", "  ", 1)
+	if tampered.Verify() {
+		t.Error("Expected tampered order to fail verification")
+	}
+	tampered = proof
+	tampered.Salt = "x" + proof.Salt
+	if tampered.Verify() {
+		t.Error("Expected tampered salt to fail verification")
+	}
+}
+
+// TestSecureIntn tests that secure indexes stay in range and cover it
+func TestSecureIntn(t *testing.T) {
+	seen := make(map[int]bool)
+	for i := 0; i < 1000; i++ {
+		v := secureIntn(5)
+		if v < 0 || v >= 5 {
+			t.Fatalf("Index %d out of range [0, 5)", v)
+		}
+		seen[v] = true
+	}
+	if len(seen) != 5 {
+		t.Errorf("Expected all 5 indexes to appear, got %d", len(seen))
+	}
+}
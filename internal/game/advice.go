@@ -0,0 +1,77 @@
+package game
+
+import (
+	"blackjack/internal/odds"
+	"blackjack/internal/strategy"
+	"fmt"
+)
+
+// Hint returns the basic strategy action for the hand being played,
+// taking into account which optional actions are legal
+func (g *Game) Hint() (strategy.Action, error) {
+	if g.state != PlayerTurn {
+		return strategy.Hit, fmt.Errorf("no hand to advise on: not player's turn")
+	}
+	upcard := g.dealer.Hand().Cards[0]
+	return strategy.Recommend(g.current().Hand(), upcard, g.rules, g.LegalActions()), nil
+}
+
+// ActionEV returns the expected value of each legal action for the hand being
+// played, as a fraction of its bet, from an infinite shoe as basic strategy assumes
+func (g *Game) ActionEV() (map[strategy.Action]float64, error) {
+	if g.state != PlayerTurn {
+		return nil, fmt.Errorf("no hand to advise on: not player's turn")
+	}
+	upcard := g.dealer.Hand().Cards[0]
+	return odds.InfiniteActionEV(g.current().Hand(), upcard, g.rules, g.LegalActions()), nil
+}
+
+// ExactEV returns the expected value of each legal action for the hand being
+// played, worked out from its exact cards and the cards the players have not seen
+func (g *Game) ExactEV() (map[strategy.Action]float64, error) {
+	if g.state != PlayerTurn {
+		return nil, fmt.Errorf("no hand to advise on: not player's turn")
+	}
+	upcard := g.dealer.Hand().Cards[0]
+	return odds.ActionEV(g.current().Hand(), upcard, g.UnseenCards(), g.rules, g.LegalActions()), nil
+}
+
+// UnseenCards counts the cards the players have not seen: those left in the
+// shoe, plus the dealer's hole card while it is face down
+func (g *Game) UnseenCards() odds.Counts {
+	unseen := odds.CountDeck(g.deck)
+	if cards := g.dealer.Hand().Cards; len(cards) > 1 && g.state != RoundOver {
+		unseen.Add(cards[1])
+	}
+	return unseen
+}
+
+// DealerOdds returns the exact chance of each dealer result from the upcard
+// and the cards the players have not seen
+func (g *Game) DealerOdds() (odds.DealerOdds, error) {
+	if g.state != PlayerTurn {
+		return odds.DealerOdds{}, fmt.Errorf("no dealer odds: not player's turn")
+	}
+	return odds.Dealer(g.dealer.Hand().Cards[0], g.UnseenCards(), g.rules), nil
+}
+
+// grade scores a decision the player is about to make against basic strategy
+func (g *Game) grade(action strategy.Action) {
+	best, err := g.Hint()
+	if err != nil {
+		return
+	}
+	score := &g.seats[g.turn].Score
+	score.Decisions++
+	if action == best {
+		score.CorrectDecisions++
+	}
+}
+
+// Accuracy returns the percentage of decisions that followed basic strategy
+func (s Score) Accuracy() float64 {
+	if s.Decisions == 0 {
+		return 0
+	}
+	return 100 * float64(s.CorrectDecisions) / float64(s.Decisions)
+}
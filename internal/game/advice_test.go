@@ -0,0 +1,125 @@
+package game
+
+import (
+	"blackjack/internal/strategy"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestHint tests basic strategy advice for the hand being played
+func TestHint(t *testing.T) {
+	tests := []struct {
+		name     string
+		cards    string
+		expected strategy.Action
+	}{
+		{"Stand on 16 against a six", "TH 6S 6C 7S", strategy.Stand},
+		{"Hit 16 against a ten", "TH TS 6C 7S", strategy.Hit},
+		{"Double 11 against a six", "6H 6S 5C 7S", strategy.Double},
+		{"Split eights", "8H 6S 8C 7S", strategy.Split},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game := newTestGame(t, WithStackedDeck(mustParseCards(t, test.cards)))
+			game.StartRound()
+			hint, err := game.Hint()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if hint != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, hint)
+			}
+
+			evs, err := game.ActionEV()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for action, ev := range evs {
+				if ev > evs[hint]+0.02 {
+					t.Errorf("Hint %s (%.3f) gives up too much against %s (%.3f)", hint, evs[hint], action, ev)
+				}
+			}
+		})
+	}
+
+	game := newTestGame(t)
+	if _, err := game.Hint(); err == nil {
+		t.Error("Expected error asking for a hint before the round starts")
+	}
+	if _, err := game.ActionEV(); err == nil {
+		t.Error("Expected error asking for EVs before the round starts")
+	}
+}
+
+// TestAccuracy tests scoring decisions against basic strategy
+func TestAccuracy(t *testing.T) {
+	// Hit a hard 12 against a six (a mistake), then stand on 17 (correct)
+	game := newTestGame(t, WithStackedDeck(mustParseCards(t, "TH 6S 2C 7S 5D")))
+	game.StartRound()
+	if err := game.PlayerHit(); err != nil {
+		t.Fatalf("Unexpected error hitting: %v", err)
+	}
+	if err := game.PlayerStand(); err != nil {
+		t.Fatalf("Unexpected error standing: %v", err)
+	}
+
+	score := game.GetScore()
+	if score.Decisions != 2 || score.CorrectDecisions != 1 || score.Accuracy() != 50 {
+		t.Errorf("Expected 1 of 2 decisions correct, got %+v", score)
+	}
+	if !strings.Contains(game.String(), "Strategy accuracy: 50% (1/2)") {
+		t.Errorf("Expected accuracy next to the score, got:\n%s", game.String())
+	}
+
+	// Refused actions are not graded
+	game.PlayerHit()
+	if game.GetScore().Decisions != 2 {
+		t.Error("Expected a refused action not to count as a decision")
+	}
+	if (Score{}).Accuracy() != 0 {
+		t.Error("Expected no accuracy without decisions")
+	}
+}
+
+// TestDealerOdds tests the dealer's chances from the cards the players haven't seen
+func TestDealerOdds(t *testing.T) {
+	// The dealer shows a six with a ten face down; an Ace and a six are left to draw
+	game := newTestGame(t, WithStackedDeck(mustParseCards(t, "9H 6S 9C TS AD 6D")))
+	game.StartRound()
+
+	unseen := game.UnseenCards()
+	if unseen.Total() != 3 || unseen[10] != 1 || unseen[11] != 1 || unseen[6] != 1 {
+		t.Errorf("Expected the hole card and the two cards left to be unseen, got %v", unseen)
+	}
+
+	d, err := game.DealerOdds()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d.Bust != 0.5 || d.Totals[17] != 0.5 {
+		t.Errorf("Expected the dealer to bust or make 17 half the time each, got %v", d)
+	}
+
+	// Standing on 18 wins whether the dealer busts or makes 17; hitting only survives the Ace
+	evs, err := game.ExactEV()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(evs[strategy.Stand]-1) > 1e-12 || math.Abs(evs[strategy.Hit]+1.0/3) > 1e-12 {
+		t.Errorf("Expected standing to win and hitting to lose a third of the bet, got %v", evs)
+	}
+
+	game.PlayerStand()
+	game.DealerPlay()
+	if got := game.UnseenCards().Total(); got != game.deck.RemainingCards() {
+		t.Errorf("Expected only the shoe to be unseen once the round is over, got %d", got)
+	}
+	if _, err := game.DealerOdds(); err == nil {
+		t.Error("Expected error asking for odds after the round")
+	}
+	if _, err := game.ExactEV(); err == nil {
+		t.Error("Expected error asking for exact EVs after the round")
+	}
+}
@@ -0,0 +1,99 @@
+package game
+
+import (
+	"blackjack/internal/player"
+	"errors"
+	"fmt"
+)
+
+// DefaultBankroll is the number of chips a player starts with unless WithBankroll is given
+const DefaultBankroll = 1000
+
+// Errors returned by PlaceBet, so callers can tell the player what went wrong
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrBetBelowMinimum   = errors.New("bet below table minimum")
+	ErrBetAboveMaximum   = errors.New("bet above table maximum")
+)
+
+// WithBankroll sets the number of chips the first player starts with
+func WithBankroll(chips int) Option {
+	return func(g *Game) error {
+		if chips < 0 {
+			return fmt.Errorf("bankroll cannot be negative: %d", chips)
+		}
+		if len(g.seats) == 0 {
+			return fmt.Errorf("no player to give a bankroll to")
+		}
+		g.seats[0].Player.Bankroll = chips
+		return nil
+	}
+}
+
+// PlaceBet wagers chips on the next round for the first seat (the only seat
+// in a single-player game). See PlaceSeatBet.
+func (g *Game) PlaceBet(amount int) error {
+	return g.PlaceSeatBet(0, amount)
+}
+
+// PlaceSeatBet wagers chips on the next round for the given seat, within the table limits
+// The chips leave the bankroll at once; placing another bet before the
+// round starts replaces the first one. Seats dealt in without a bet play
+// for nothing.
+func (g *Game) PlaceSeatBet(seat, amount int) error {
+	if g.RoundInProgress() {
+		return fmt.Errorf("cannot bet: round in progress")
+	}
+	if seat < 0 || seat >= len(g.seats) {
+		return fmt.Errorf("cannot bet: no seat %d", seat)
+	}
+	p := g.seats[seat].Player
+
+	switch {
+	case amount < g.rules.MinBet:
+		return fmt.Errorf("%w: %d is less than %d", ErrBetBelowMinimum, amount, g.rules.MinBet)
+	case amount > g.rules.MaxBet:
+		return fmt.Errorf("%w: %d is more than %d", ErrBetAboveMaximum, amount, g.rules.MaxBet)
+	case amount > p.Bankroll+p.Bet:
+		return fmt.Errorf("%w: bet of %d with %d chips", ErrInsufficientFunds, amount, p.Bankroll+p.Bet)
+	}
+
+	// Return any earlier bet before taking the new one
+	p.Bankroll += p.Bet - amount
+	p.Bet = amount
+	return nil
+}
+
+// settleBet pays the seat the chips returned by a hand's result
+// (nothing for a loss, the bet for a push, the bet plus winnings for a win)
+func (g *Game) settleBet(seat *Seat, hand *player.Hand, returned int) {
+	seat.RoundNet += returned - hand.Bet
+	seat.Player.Bankroll += returned
+	hand.Bet = 0
+}
+
+// Bet returns the chips the seat has wagered on the current round, across
+// every hand and any insurance
+func (s *Seat) Bet() int {
+	total := s.Player.Bet + s.Player.Insurance
+	for _, hand := range s.Player.Hands {
+		total += hand.Bet
+	}
+	return total
+}
+
+// Bankroll returns the chips the first seat has, not counting any bet on the table
+func (g *Game) Bankroll() int {
+	return g.seats[0].Player.Bankroll
+}
+
+// Bet returns the chips the first seat has wagered on the current round
+func (g *Game) Bet() int {
+	return g.seats[0].Bet()
+}
+
+// RoundNet returns the chips the first seat won (positive) or lost (negative)
+// in the last settled round
+func (g *Game) RoundNet() int {
+	return g.seats[0].RoundNet
+}
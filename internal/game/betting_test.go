@@ -0,0 +1,158 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"blackjack/internal/rules"
+)
+
+// TestPlaceBet tests bet limits and bankroll checks
+func TestPlaceBet(t *testing.T) {
+	game := newTestGame(t, WithBankroll(100))
+	if game.Bankroll() != 100 {
+		t.Fatalf("Expected bankroll 100, got %d", game.Bankroll())
+	}
+
+	tests := []struct {
+		amount int
+		err    error
+	}{
+		{5, ErrBetBelowMinimum},
+		{600, ErrBetAboveMaximum},
+		{150, ErrInsufficientFunds},
+	}
+	for _, test := range tests {
+		if err := game.PlaceBet(test.amount); !errors.Is(err, test.err) {
+			t.Errorf("Bet of %d: expected %v, got %v", test.amount, test.err, err)
+		}
+	}
+	if game.Bankroll() != 100 || game.Bet() != 0 {
+		t.Error("Rejected bets should not move any chips")
+	}
+
+	// A new bet replaces the old one, and may use the chips the old one freed
+	if err := game.PlaceBet(60); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := game.PlaceBet(100); err != nil {
+		t.Fatalf("Unexpected error replacing bet: %v", err)
+	}
+	if game.Bankroll() != 0 || game.Bet() != 100 {
+		t.Errorf("Expected 0 in bankroll and 100 bet, got %d and %d", game.Bankroll(), game.Bet())
+	}
+
+	game.StartRound()
+	if game.state == PlayerTurn {
+		if err := game.PlaceBet(10); err == nil {
+			t.Error("Expected error betting during a round")
+		}
+	}
+
+	if _, err := NewGame("Test Player", WithBankroll(-1)); err == nil {
+		t.Error("Expected error for negative bankroll")
+	}
+	if newTestGame(t).Bankroll() != DefaultBankroll {
+		t.Error("Expected default bankroll")
+	}
+}
+
+// TestSettleBet tests payouts for each kind of result
+func TestSettleBet(t *testing.T) {
+	tests := []struct {
+		name    string
+		payout  rules.Payout
+		cards   string
+		net     int
+		standOn bool
+	}{
+		// Player first, then dealer, alternately; then the dealer draws
+		{"Win pays 1:1", rules.ThreeToTwo, "TH TS 9C 8S", 20, true},
+		{"Loss takes the bet", rules.ThreeToTwo, "TH TS 7C 8S", -20, true},
+		{"Push returns the bet", rules.ThreeToTwo, "TH TS 8C 8S", 0, true},
+		{"Dealer bust pays 1:1", rules.ThreeToTwo, "TH TS 8C 6S KD", 20, true},
+		{"BlackJack pays 3:2", rules.ThreeToTwo, "AH TS KC 8S", 30, false},
+		{"BlackJack pays 6:5", rules.SixToFive, "AH TS KC 8S", 24, false},
+		{"BlackJack pays 1:1", rules.EvenMoney, "AH TS KC 8S", 20, false},
+		{"BlackJack against BlackJack pushes", rules.ThreeToTwo, "AH AS KC KS", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := rules.DefaultTableRules()
+			table.BlackjackPayout = test.payout
+			game := newTestGame(t, WithRules(table), WithBankroll(100), WithStackedDeck(mustParseCards(t, test.cards)))
+
+			if err := game.PlaceBet(20); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			game.StartRound()
+			game.DeclineInsurance() // Offered when the dealer shows an Ace
+			if test.standOn {
+				game.PlayerStand()
+			}
+			game.DealerPlay()
+			game.GetResult()
+
+			if game.RoundNet() != test.net {
+				t.Errorf("Expected net %d, got %d", test.net, game.RoundNet())
+			}
+			if game.Bankroll() != 100+test.net || game.Bet() != 0 {
+				t.Errorf("Expected bankroll %d with no bet, got %d with %d bet", 100+test.net, game.Bankroll(), game.Bet())
+			}
+		})
+	}
+
+	t.Run("Bust loses the bet", func(t *testing.T) {
+		game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "TH TS 6C 8S KD")))
+		game.PlaceBet(50)
+		game.StartRound()
+		game.PlayerHit()
+		game.GetResult()
+		if game.Bankroll() != 50 || game.RoundNet() != -50 {
+			t.Errorf("Expected 50 left after losing 50, got %d (net %d)", game.Bankroll(), game.RoundNet())
+		}
+	})
+}
+
+// TestSettleOnce tests that a round's bets are settled once as it ends,
+// however often the result is asked for
+func TestSettleOnce(t *testing.T) {
+	// Test Player T T against the dealer's 9 8, twice
+	cards := "TH TS 9C 8S TD TC 9D 8D"
+
+	t.Run("Result asked for twice", func(t *testing.T) {
+		game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, cards)))
+		game.PlaceBet(10)
+		game.StartRound()
+		game.PlayerStand()
+		game.DealerPlay()
+
+		first := game.GetResult()
+		if second := game.GetResult(); second != first || first != "Player wins!" {
+			t.Errorf("Expected the same win reported twice, got %q and %q", first, second)
+		}
+		if game.Bankroll() != 110 || game.RoundNet() != 10 || game.GetScore().Wins != 1 {
+			t.Errorf("Expected one win of 10, got %d chips (net %d) and %d wins", game.Bankroll(), game.RoundNet(), game.GetScore().Wins)
+		}
+	})
+
+	t.Run("Result never asked for", func(t *testing.T) {
+		game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, cards)))
+		game.PlaceBet(10)
+		game.StartRound()
+		game.PlayerStand()
+		game.DealerPlay()
+
+		game.PlaceBet(10)
+		if err := game.StartRound(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if game.Bankroll() != 100 || game.Bet() != 10 || game.GetScore().Wins != 1 {
+			t.Errorf("Expected the first win paid before the next deal, got %d chips, %d bet and %d wins", game.Bankroll(), game.Bet(), game.GetScore().Wins)
+		}
+		if game.GetResult() != "" {
+			t.Error("Expected no result until the new round ends")
+		}
+	})
+}
@@ -2,9 +2,14 @@
 package game
 
 import (
+	"blackjack/internal/count"
 	"blackjack/internal/deck"
 	"blackjack/internal/player"
+	"blackjack/internal/rules"
+	"blackjack/internal/strategy"
 	"fmt"
+	"math/rand"
+	"strings"
 )
 
 // GameState represents the current state of the game
@@ -12,99 +17,393 @@ type GameState int
 
 const (
 	// Game states
-	WaitingToStart GameState = iota // Waiting for the game to start
-	PlayerTurn                      // Player's turn to act
-	DealerTurn                      // Dealer's turn to act
-	RoundOver                       // Round is complete
+	WaitingToStart   GameState = iota // Waiting for the game to start
+	PlayerTurn                        // A player's turn to act (see Turn)
+	DealerTurn                        // Dealer's turn to act
+	RoundOver                         // Round is complete
+	InsuranceOffered                  // Dealer shows an Ace and a player may insure (see Turn)
 )
 
+// Score tracks the results of every round a seat played in the session
 type Score struct {
-	Wins   int
-	Losses int
-	Pushes int
+	Wins       int `json:"wins"`
+	Losses     int `json:"losses"`
+	Pushes     int `json:"pushes"`
+	Surrenders int `json:"surrenders"`
+
+	// Insurance side bets are scored apart from the hands they insure
+	InsuranceWins   int `json:"insuranceWins"`
+	InsuranceLosses int `json:"insuranceLosses"`
+
+	// Hit, stand, double, split and surrender decisions, and how many followed basic strategy
+	Decisions        int `json:"decisions"`
+	CorrectDecisions int `json:"correctDecisions"`
 }
 
-// Game represents a BlackJack game session
+// Game represents a BlackJack game session: up to MaxSeats players sharing
+// one shoe against the dealer
 type Game struct {
-	player *player.Player // The player
-	dealer *player.Player // The dealer
-	deck   *deck.Deck     // The game's deck
-	state  GameState      // Current game state
-	score  Score
+	seats  []*Seat          // Occupied seats, in turn order
+	turn   int              // Index of the seat whose turn it is
+	dealer *player.Player   // The dealer
+	deck   *deck.Deck       // The game's deck
+	state  GameState        // Current game state
+	rules  rules.TableRules // Rules in force at the table
+
+	shuffled bool        // Whether the deck was reshuffled during the last round
+	source   rand.Source // Random source for shuffling, if set by WithSeed or WithSource
+	secure   bool        // Whether to shuffle with crypto/rand (see WithSecureShuffle)
+
+	system  count.System   // Counting system for the counter, if set by WithCountingSystem
+	counter *count.Counter // Counts every card seen from the shoe (see Counter)
+
+	result string // Outcome of the last round, settled as it ended (see GetResult)
+
+	peekPending bool // The dealer will check for BlackJack once every seat has had its chance at early surrender
+}
+
+// Option configures a Game when it is created by NewGame
+type Option func(*Game) error
+
+// WithDeck makes the game deal from the given deck or shoe (see deck.NewShoe)
+// instead of a single 52-card deck
+func WithDeck(d *deck.Deck) Option {
+	return func(g *Game) error {
+		if d == nil {
+			return fmt.Errorf("deck cannot be nil")
+		}
+		g.deck = d
+		return nil
+	}
+}
+
+// WithRules sets the table rules the game enforces
+// The game deals from a shoe built to the rules unless WithDeck is also given,
+// in which case the rules' deck count and penetration follow that deck
+func WithRules(r rules.TableRules) Option {
+	return func(g *Game) error {
+		g.rules = r
+		return nil
+	}
+}
+
+// WithStackedDeck makes the game deal exactly the given cards, in order
+// Cards are dealt to the player, the dealer, the player, the dealer, then to
+// whoever acts next, so tests can script exact scenarios. At a table with
+// several seats each round of the deal goes to every seat before the dealer. Without a peek
+// (see rules.TableRules.DealerPeeks) the dealer's second card comes after
+// the player's.
+func WithStackedDeck(cards []deck.Card) Option {
+	return func(g *Game) error {
+		d, err := deck.NewStackedDeck(cards)
+		if err != nil {
+			return err
+		}
+		g.deck = d
+		return nil
+	}
+}
+
+// WithSource makes every shuffle of the game's deck use the given random source
+func WithSource(src rand.Source) Option {
+	return func(g *Game) error {
+		if src == nil {
+			return fmt.Errorf("random source cannot be nil")
+		}
+		g.source = src
+		return nil
+	}
+}
+
+// WithSeed makes the game reproducible: the same seed always deals the same rounds
+func WithSeed(seed int64) Option {
+	return WithSource(rand.NewSource(seed))
+}
+
+// WithSecureShuffle shuffles with crypto/rand and publishes a commitment to each
+// shoe's order before the deal (see ShuffleCommitment and RevealShuffle).
+// It cannot be combined with WithSeed or WithSource.
+func WithSecureShuffle() Option {
+	return func(g *Game) error {
+		g.secure = true
+		return nil
+	}
 }
 
-// NewGame creates a new BlackJack game
-func NewGame(playerName string) *Game {
+// WithCountingSystem makes the game's counter use the given system instead of Hi-Lo
+func WithCountingSystem(system count.System) Option {
+	return func(g *Game) error {
+		if system.Name == "" {
+			return fmt.Errorf("counting system must have a name")
+		}
+		g.system = system
+		return nil
+	}
+}
+
+// NewGame creates a new BlackJack game with the named player in the first seat
+// More players can join with SitDown
+func NewGame(playerName string, opts ...Option) (*Game, error) {
 	game := &Game{
-		player: player.NewPlayer(playerName),
 		dealer: player.NewPlayer("Dealer"),
-		deck:   deck.NewDeck(),
 		state:  WaitingToStart,
-		score:  Score{}, // Initialize score to zero
+		rules:  rules.DefaultTableRules(),
+	}
+	game.seats = []*Seat{{Player: player.NewPlayer(playerName)}}
+	game.seats[0].Player.Bankroll = DefaultBankroll
+
+	// Apply the caller's options in order
+	for _, opt := range opts {
+		if err := opt(game); err != nil {
+			return nil, fmt.Errorf("invalid game option: %v", err)
+		}
+	}
+
+	if err := game.rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid table rules: %v", err)
+	}
+
+	// Build the shoe the rules call for, unless the caller brought their own
+	if game.deck == nil {
+		shoe, err := deck.NewShoe(game.rules.Decks)
+		if err != nil {
+			return nil, err
+		}
+		shoe.SetPenetration(game.rules.Penetration) // Already checked by Validate
+		game.deck = shoe
+	} else if !game.deck.Stacked() {
+		game.rules.Decks = game.deck.DeckCount()
+		game.rules.Penetration = game.deck.Penetration()
 	}
 
-	// Shuffle the deck
+	// Shuffle the deck, using the caller's random source if one was given
+	if err := game.configureShuffle(); err != nil {
+		return nil, err
+	}
 	game.deck.Shuffle()
+	game.startCount()
+
+	return game, nil
+}
 
-	return game
+// configureShuffle hands the random source or secure mode chosen by the options to the deck
+func (g *Game) configureShuffle() error {
+	switch {
+	case g.secure && g.source != nil:
+		return fmt.Errorf("invalid game option: secure shuffling cannot use a seed or random source")
+	case g.secure:
+		g.deck.SetSecure(true)
+	case g.source != nil:
+		g.deck.SetSource(g.source)
+	}
+	return nil
+}
+
+// startCount sets up the counter for the game's shoe, with Hi-Lo unless another system was chosen
+func (g *Game) startCount() {
+	if g.system.Name == "" {
+		g.system = count.HiLo
+	}
+	g.counter = count.NewCounter(g.system, g.deck.DeckCount())
+}
+
+// draw deals a card face up, counting it as everyone at the table sees it
+func (g *Game) draw() (deck.Card, error) {
+	card, err := g.deck.DrawCard()
+	if err == nil {
+		g.counter.Observe(card)
+	}
+	return card, err
 }
 
+// cardsPerHand is how many cards the shoe must hold for each hand at the
+// table, the dealer's included, before a round is dealt
+const cardsPerHand = 5
+
 // StartRound begins a new round of BlackJack
+// Every seat is dealt in, and the first seat to act takes the turn
 func (g *Game) StartRound() error {
-	// Reset hands
-	g.player.ClearHand()
+	if g.RoundInProgress() {
+		return fmt.Errorf("cannot start round: round in progress")
+	}
+
+	// Reset hands, moving each bet onto the player's first hand
+	for _, seat := range g.seats {
+		p := seat.Player
+		p.ClearHand()
+		p.Hand().Bet = p.Bet
+		p.Bet = 0
+		p.Insurance = 0
+		p.EvenMoney = false
+	}
 	g.dealer.ClearHand()
+	g.shuffled = false
+	g.result = ""
+	g.peekPending = false
 
-	// Check if we need a new deck (less than 20 cards remaining)
-	if g.deck.RemainingCards() < 20 {
-		g.deck = deck.NewDeck()
-		g.deck.Shuffle()
+	// Players who sat down since the last round may have left the shoe too short
+	if g.shortShoe() {
+		g.reshuffle()
 	}
 
-	// Deal initial cards
-	// First card to player and dealer
-	card, err := g.deck.DrawCard()
-	if err != nil {
-		return fmt.Errorf("failed to deal card: %v", err)
+	if err := g.deal(); err != nil {
+		// No round was dealt, so the bets go back to the players
+		for _, seat := range g.seats {
+			p := seat.Player
+			p.Bet = p.Hand().Bet
+			p.ClearHand()
+		}
+		g.dealer.ClearHand()
+		return err
 	}
-	g.player.AddCard(card)
 
-	card, err = g.deck.DrawCard()
+	// Offer insurance on a dealer Ace, otherwise check for BlackJacks
+	g.turn = -1
+	return g.nextInsurance()
+}
+
+// deal gives every player and the dealer their first two cards
+func (g *Game) deal() error {
+	// First card to every player, then the dealer
+	if err := g.dealToSeats(); err != nil {
+		return err
+	}
+	card, err := g.draw()
 	if err != nil {
 		return fmt.Errorf("failed to deal card to dealer: %v", err)
 	}
 	g.dealer.AddCard(card)
 
-	// Second card to player and dealer
-	card, err = g.deck.DrawCard()
-	if err != nil {
-		return fmt.Errorf("failed to deal card: %v", err)
+	// Second card to every player and the dealer
+	if err := g.dealToSeats(); err != nil {
+		return err
+	}
+
+	// Without a peek there is no hole card: the dealer's second card comes after the players act
+	if g.rules.DealerPeeks {
+		card, err = g.deck.DrawCard() // Face down, so not counted until it is revealed
+		if err != nil {
+			return fmt.Errorf("failed to deal card to dealer: %v", err)
+		}
+		g.dealer.AddCard(card)
+	}
+	return nil
+}
+
+// dealToSeats deals one card to each seat in turn
+func (g *Game) dealToSeats() error {
+	for _, seat := range g.seats {
+		card, err := g.draw()
+		if err != nil {
+			return fmt.Errorf("failed to deal card: %v", err)
+		}
+		seat.Player.AddCard(card)
+	}
+	return nil
+}
+
+// beginPlay hands the round to the first player with a decision to make, or to
+// the dealer if every player has BlackJack. A peeking dealer first checks for
+// BlackJack under an Ace or ten, ending the round at once if they have it.
+// Under early surrender the check waits until every seat has had the
+// chance to surrender.
+func (g *Game) beginPlay() error {
+	if g.rules.DealerPeeks && g.dealer.Hand().Cards[0].Value() >= 10 {
+		if g.rules.Surrender == rules.EarlySurrender && !g.allBlackjack() {
+			g.peekPending = true
+		} else if g.peek() {
+			return nil
+		}
+	}
+
+	for _, seat := range g.seats {
+		if seat.Player.HasBlackjack() {
+			seat.Player.Stand()
+		}
+	}
+	g.turn = -1
+	return g.nextSeat()
+}
+
+// allBlackjack reports whether every player was dealt BlackJack
+func (g *Game) allBlackjack() bool {
+	for _, seat := range g.seats {
+		if !seat.Player.HasBlackjack() {
+			return false
+		}
+	}
+	return true
+}
+
+// peek checks the dealer's hole card, ending the round if the dealer has BlackJack
+func (g *Game) peek() bool {
+	g.peekPending = false
+	if !g.dealer.HasBlackjack() {
+		return false
+	}
+	g.endRound()
+	return true
+}
+
+// resolvePeek carries out a peek that waited for early surrender, before a
+// decision that leaves no seat still to choose whether to surrender. It
+// reports whether the round ended.
+func (g *Game) resolvePeek() bool {
+	if !g.peekPending {
+		return false
+	}
+	for _, seat := range g.seats[g.turn+1:] {
+		if undecided(seat) {
+			return false
+		}
 	}
-	g.player.AddCard(card)
+	return g.peek()
+}
 
-	card, err = g.deck.DrawCard()
+// undecided reports whether a seat has yet to make the first decision on its
+// hand, and so may still surrender
+func undecided(seat *Seat) bool {
+	p := seat.Player
+	return len(p.Hands) == 1 && len(p.Hand().Cards) == 2 && p.Hand().State == player.Playing
+}
+
+// dealHoleCard gives the dealer their second card when there was no hole card
+// and the round ends without the dealer playing, so insurance and surrender can settle
+func (g *Game) dealHoleCard() error {
+	if len(g.dealer.Hand().Cards) != 1 {
+		return nil
+	}
+	card, err := g.draw()
 	if err != nil {
 		return fmt.Errorf("failed to deal card to dealer: %v", err)
 	}
 	g.dealer.AddCard(card)
+	return nil
+}
 
-	// Check for player BlackJack
-	if g.player.HasBlackjack() {
-		g.player.Stand()
-		g.state = DealerTurn
-	} else {
-		g.state = PlayerTurn
-	}
+// RoundInProgress reports whether a round has been dealt and not yet finished
+func (g *Game) RoundInProgress() bool {
+	return g.state == PlayerTurn || g.state == DealerTurn || g.state == InsuranceOffered
+}
 
-	return nil
+// ShuffleCommitment returns the commitment to the current shoe's order
+// It is empty unless the game was created with WithSecureShuffle
+func (g *Game) ShuffleCommitment() string {
+	return g.deck.Commitment()
+}
+
+// RevealShuffle returns the proof for the previous shoe once it has been replaced,
+// so players can check it against the commitment published before its deal
+func (g *Game) RevealShuffle() (deck.ShuffleProof, bool) {
+	return g.deck.PreviousProof()
 }
 
 // GetDealerVisibleCard returns the dealer's face-up card
 func (g *Game) GetDealerVisibleCard() (deck.Card, error) {
-	if len(g.dealer.Hand) == 0 {
+	if len(g.dealer.Hand().Cards) == 0 {
 		return deck.Card{}, fmt.Errorf("dealer has no cards")
 	}
-	return g.dealer.Hand[0], nil
+	return g.dealer.Hand().Cards[0], nil
 }
 
 // PlayerHit handles the player's request to hit (take another card)
@@ -112,17 +411,24 @@ func (g *Game) PlayerHit() error {
 	if g.state != PlayerTurn {
 		return fmt.Errorf("cannot hit: not player's turn")
 	}
+	if g.current().Hand().SplitAces() {
+		return fmt.Errorf("cannot hit: split Aces receive one card only")
+	}
+	g.grade(strategy.Hit)
+	if g.resolvePeek() {
+		return nil
+	}
 
-	card, err := g.deck.DrawCard()
+	card, err := g.draw()
 	if err != nil {
 		return fmt.Errorf("failed to draw card: %v", err)
 	}
 
-	g.player.AddCard(card)
+	g.current().AddCard(card)
 
 	// Check if player busted
-	if g.player.State == player.Busted {
-		g.state = RoundOver
+	if g.current().Evaluate().Busted {
+		return g.playOn()
 	}
 
 	return nil
@@ -133,66 +439,374 @@ func (g *Game) PlayerStand() error {
 	if g.state != PlayerTurn {
 		return fmt.Errorf("cannot stand: not player's turn")
 	}
+	g.grade(strategy.Stand)
+	if g.resolvePeek() {
+		return nil
+	}
+
+	g.current().Stand()
+	return g.playOn()
+}
+
+// playOn moves past every hand that needs no more decisions, dealing the
+// second card to each split hand as it comes up. Split Aces stand on their
+// one card unless they can be split again. Once every hand is done the turn
+// passes to the next seat.
+func (g *Game) playOn() error {
+	for {
+		hand := g.current().Hand()
+		if len(hand.Cards) == 1 {
+			card, err := g.draw()
+			if err != nil {
+				return fmt.Errorf("failed to deal card to split hand: %v", err)
+			}
+			hand.AddCard(card)
+			if hand.SplitAces() && !g.CanSplit() {
+				hand.Stand()
+			}
+		}
+		if hand.State == player.Playing {
+			return nil
+		}
+		if !g.current().NextHand() {
+			return g.nextSeat()
+		}
+	}
+}
+
+// CanDouble reports whether the player may double down right now:
+// on their first two cards, if the table's double rule allows the total
+// and the bankroll can match the bet
+func (g *Game) CanDouble() bool {
+	return g.checkDouble() == nil
+}
+
+// checkDouble explains why the player may not double, or returns nil if they may
+func (g *Game) checkDouble() error {
+	if g.state != PlayerTurn {
+		return fmt.Errorf("cannot double: not player's turn")
+	}
+
+	hand := g.current().Hand()
+	switch {
+	case len(hand.Cards) != 2:
+		return fmt.Errorf("cannot double: only allowed on the first two cards")
+	case hand.SplitAces():
+		return fmt.Errorf("cannot double: split Aces receive one card only")
+	case hand.Split && !g.rules.DoubleAfterSplit:
+		return fmt.Errorf("cannot double: table does not allow doubling after a split")
+	case !g.rules.Double.Allows(hand.Evaluate().Total):
+		return fmt.Errorf("cannot double: table only allows doubling on %s", g.rules.Double)
+	case g.current().Bankroll < hand.Bet:
+		return fmt.Errorf("cannot double: %w", ErrInsufficientFunds)
+	}
+	return nil
+}
+
+// PlayerDouble handles the player's request to double down: the bet is
+// doubled, exactly one more card is dealt and the player's turn ends
+func (g *Game) PlayerDouble() error {
+	if err := g.checkDouble(); err != nil {
+		return err
+	}
+	g.grade(strategy.Double)
+	if g.resolvePeek() {
+		return nil
+	}
 
-	g.player.Stand()
-	g.state = DealerTurn
+	card, err := g.draw()
+	if err != nil {
+		return fmt.Errorf("failed to draw card: %v", err)
+	}
+
+	hand := g.current().Hand()
+	g.current().Bankroll -= hand.Bet
+	hand.Bet *= 2
+	hand.Doubled = true
+	hand.AddCard(card)
+
+	if !hand.Evaluate().Busted {
+		hand.Stand()
+	}
+	return g.playOn()
+}
+
+// CanSplit reports whether the player may split the hand being played:
+// a pair, within the table's split limit, with chips to match the bet
+func (g *Game) CanSplit() bool {
+	return g.checkSplit() == nil
+}
+
+// checkSplit explains why the player may not split, or returns nil if they may
+func (g *Game) checkSplit() error {
+	if g.state != PlayerTurn {
+		return fmt.Errorf("cannot split: not player's turn")
+	}
+
+	hand := g.current().Hand()
+	switch {
+	case !hand.Evaluate().Pair:
+		return fmt.Errorf("cannot split: only a pair can be split")
+	case g.current().Splits() >= g.rules.MaxSplits:
+		return fmt.Errorf("cannot split: table allows %d splits per round", g.rules.MaxSplits)
+	case hand.SplitAces() && !g.rules.ResplitAces:
+		return fmt.Errorf("cannot split: split Aces may not be split again")
+	case g.current().Bankroll < hand.Bet:
+		return fmt.Errorf("cannot split: %w", ErrInsufficientFunds)
+	}
 	return nil
 }
 
+// PlayerSplit handles the player's request to split a pair: each card starts
+// a new hand with its own copy of the bet, and the hands are played in turn
+func (g *Game) PlayerSplit() error {
+	if err := g.checkSplit(); err != nil {
+		return err
+	}
+	g.grade(strategy.Split)
+	if g.resolvePeek() {
+		return nil
+	}
+
+	bet := g.current().Hand().Bet
+	if _, err := g.current().Split(); err != nil {
+		return err
+	}
+	g.current().Bankroll -= bet
+	return g.playOn()
+}
+
+// CanSurrender reports whether the player may surrender: only as the first
+// decision on the hand dealt, at a table that offers surrender
+func (g *Game) CanSurrender() bool {
+	return g.checkSurrender() == nil
+}
+
+// checkSurrender explains why the player may not surrender, or returns nil if they may
+func (g *Game) checkSurrender() error {
+	switch {
+	case g.state != PlayerTurn:
+		return fmt.Errorf("cannot surrender: not player's turn")
+	case g.rules.Surrender == rules.NoSurrender:
+		return fmt.Errorf("cannot surrender: table does not offer surrender")
+	case len(g.current().Hands) != 1 || len(g.current().Hand().Cards) != 2:
+		return fmt.Errorf("cannot surrender: only allowed as the first decision")
+	}
+	return nil
+}
+
+// PlayerSurrender handles the player's request to give up the hand for half
+// the bet. Under late surrender a dealer BlackJack still takes the whole bet.
+func (g *Game) PlayerSurrender() error {
+	if err := g.checkSurrender(); err != nil {
+		return err
+	}
+	g.grade(strategy.Surrender) // Early surrender comes before the dealer checks for BlackJack
+
+	g.current().Hand().State = player.Surrendered
+	return g.playOn()
+}
+
+// Hands returns the number of hands held by the player whose turn it is, and which one is being played (from 1)
+func (g *Game) Hands() (count, active int) {
+	return len(g.current().Hands), g.current().Active + 1
+}
+
+// LegalActions reports which optional actions the player may take right now
+func (g *Game) LegalActions() rules.Actions {
+	return rules.Actions{
+		Double:    g.CanDouble(),
+		Split:     g.CanSplit(),
+		Surrender: g.CanSurrender(),
+	}
+}
+
 // DealerPlay handles the dealer's turn
 func (g *Game) DealerPlay() error {
 	if g.state != DealerTurn {
 		return fmt.Errorf("not dealer's turn")
 	}
 
-	// Dealer must hit on 16 and below, stand on 17 and above
-	for g.dealer.GetHandValue() < 17 {
-		card, err := g.deck.DrawCard()
+	// Dealer must hit on 16 and below, stand on 17 and above (hitting soft 17 under H17)
+	for {
+		value := g.dealer.Evaluate()
+		if !g.rules.DealerHits(value.Total, value.Soft) {
+			break
+		}
+
+		card, err := g.draw()
 		if err != nil {
 			return fmt.Errorf("failed to draw card: %v", err)
 		}
 		g.dealer.AddCard(card)
 	}
 
-	g.state = RoundOver
+	g.endRound()
 	return nil
 }
 
-// GetResult returns the game result from the player's perspective
+// endRound finishes the round, turning over the dealer's hole card and
+// settling the bets, and reshuffles the deck if the cut card came out during it or too few cards
+// are left for the next round
+func (g *Game) endRound() {
+	g.state = RoundOver
+	g.turn = 0
+	if cards := g.dealer.Hand().Cards; g.rules.DealerPeeks && len(cards) > 1 {
+		g.counter.Observe(cards[1])
+	}
+	g.result = g.settle()
+
+	if g.deck.CutCardReached() || g.shortShoe() {
+		g.reshuffle()
+	}
+}
+
+// shortShoe reports whether the shoe has too few cards left to deal the whole
+// table a round without running dry
+func (g *Game) shortShoe() bool {
+	return !g.deck.Stacked() && g.deck.RemainingCards() < cardsPerHand*(len(g.seats)+1)
+}
+
+// reshuffle puts every card back in the shoe and shuffles it, starting the count again
+func (g *Game) reshuffle() {
+	g.deck.Reset()
+	g.deck.Shuffle()
+	g.counter.Reset()
+	g.shuffled = true
+}
+
+// Counter returns the count of every card the players have seen from the
+// shoe, including the dealer's hole card once it is turned over. The count
+// starts again whenever the shoe is reshuffled.
+func (g *Game) Counter() *count.Counter {
+	return g.counter
+}
+
+// Shuffled reports whether the deck was reshuffled during the last round, at
+// the end because the cut card came out or too few cards were left, or before
+// the deal because more players had sat down. It is cleared when the next
+// round starts.
+func (g *Game) Shuffled() bool {
+	return g.shuffled
+}
+
+// GetResult returns the result of the last round from the players' perspective
+// The bets are settled once, as the round ends, so asking again repeats the
+// same result without paying twice. At a table with several seats each line
+// of the result starts with the player's name.
 func (g *Game) GetResult() string {
-	result := ""
-	playerValue := g.player.GetHandValue()
-	dealerValue := g.dealer.GetHandValue()
+	return g.result
+}
+
+// settle decides every seat's hands on their own against the dealer,
+// recording the results in the seat's score and paying out the bets
+func (g *Game) settle() string {
+	dealerValue := g.dealer.Evaluate()
+
+	results := make([]string, 0, len(g.seats))
+	for _, seat := range g.seats {
+		result := g.settleSeat(seat, dealerValue)
+		if len(g.seats) > 1 {
+			prefix := seat.Player.Name + ": "
+			result = prefix + strings.ReplaceAll(result, "\n", "\n"+prefix)
+		}
+		results = append(results, result)
+	}
+	return strings.Join(results, "\n")
+}
+
+// settleSeat settles the insurance and every hand of one seat, returning the results
+func (g *Game) settleSeat(seat *Seat, dealerValue player.HandValue) string {
+	seat.RoundNet = 0
+	p := seat.Player
+
+	results := make([]string, 0, len(p.Hands)+1)
+	if p.Insurance > 0 {
+		results = append(results, g.settleInsurance(seat, dealerValue))
+	}
+	for i, hand := range p.Hands {
+		result := g.settleHand(seat, hand, dealerValue)
+		if len(p.Hands) > 1 {
+			result = fmt.Sprintf("Hand %d: %s", i+1, result)
+		}
+		results = append(results, result)
+	}
+	return strings.Join(results, "\n")
+}
+
+// settleHand decides one hand against the dealer and settles its bet
+func (g *Game) settleHand(seat *Seat, hand *player.Hand, dealerValue player.HandValue) string {
+	playerValue := hand.Evaluate()
+	bet := hand.Bet
+	score := &seat.Score
 
 	switch {
-	case g.player.State == player.Busted:
-		result = "Player busted! Dealer wins!"
-		g.score.Losses++
-	case g.dealer.State == player.Busted:
-		result = "Dealer busted! Player wins!"
-		g.score.Wins++
-	case g.player.State == player.BlackJack && g.dealer.State != player.BlackJack:
-		result = "BlackJack! Player wins!"
-		g.score.Wins++
-	case g.dealer.State == player.BlackJack && g.player.State != player.BlackJack:
-		result = "Dealer has BlackJack! Dealer wins!"
-		g.score.Losses++
-	case playerValue > dealerValue:
-		result = "Player wins!"
-		g.score.Wins++
-	case dealerValue > playerValue:
-		result = "Dealer wins!"
-		g.score.Losses++
+	case seat.Player.EvenMoney:
+		score.Wins++
+		g.settleBet(seat, hand, 2*bet)
+		return "Even money! Player wins!"
+	case hand.State == player.Surrendered && dealerValue.Natural && g.rules.Surrender == rules.LateSurrender:
+		score.Losses++
+		g.settleBet(seat, hand, 0)
+		return "Dealer has BlackJack! Late surrender doesn't count, dealer wins!"
+	case hand.State == player.Surrendered:
+		score.Surrenders++
+		g.settleBet(seat, hand, bet/2)
+		return "Player surrendered! Half the bet is returned."
+	case dealerValue.Natural && g.rules.DealerPeeks && (hand.Doubled || hand.Split):
+		// Doubled or split while the dealer's check waited for early surrender:
+		// the check comes first at the table, so only the original bet is lost
+		if hand != seat.Player.Hands[0] {
+			score.Pushes++
+			g.settleBet(seat, hand, bet)
+			return "Dealer has BlackJack! The split bet is returned."
+		}
+		original := bet
+		if hand.Doubled {
+			original /= 2
+		}
+		score.Losses++
+		g.settleBet(seat, hand, bet-original)
+		return "Dealer has BlackJack! Dealer wins the original bet."
+	case playerValue.Busted:
+		score.Losses++
+		g.settleBet(seat, hand, 0)
+		return "Player busted! Dealer wins!"
+	case playerValue.Natural && !dealerValue.Natural:
+		score.Wins++
+		g.settleBet(seat, hand, bet+g.rules.BlackjackPayout.Pay(bet))
+		return "BlackJack! Player wins!"
+	case dealerValue.Busted:
+		score.Wins++
+		g.settleBet(seat, hand, 2*bet)
+		return "Dealer busted! Player wins!"
+	case dealerValue.Natural && !playerValue.Natural:
+		score.Losses++
+		g.settleBet(seat, hand, 0)
+		return "Dealer has BlackJack! Dealer wins!"
+	case playerValue.Total > dealerValue.Total:
+		score.Wins++
+		g.settleBet(seat, hand, 2*bet)
+		return "Player wins!"
+	case dealerValue.Total > playerValue.Total:
+		score.Losses++
+		g.settleBet(seat, hand, 0)
+		return "Dealer wins!"
 	default:
-		result = "Push! It's a tie!"
-		g.score.Pushes++
+		score.Pushes++
+		g.settleBet(seat, hand, bet)
+		return "Push! It's a tie!"
 	}
-	return result
 }
 
-// GetScore returns the current game score
+// GetScore returns the session score of the first seat (the only seat in a single-player game)
 func (g *Game) GetScore() Score {
-	return g.score
+	return g.seats[0].Score
+}
+
+// Rules returns the table rules in force
+func (g *Game) Rules() rules.TableRules {
+	return g.rules
 }
 
 // GetState returns the current game state
@@ -206,11 +820,34 @@ func (g *Game) String() string {
 	dealerInfo := fmt.Sprintf("Dealer: %s\n", g.dealer)
 	if g.state != RoundOver {
 		// Hide dealer's second card during play
-		if len(g.dealer.Hand) > 1 {
-			dealerInfo = fmt.Sprintf("Dealer: Player: Dealer\nHand: %s, (Hidden card)\nValue: ?\n", g.dealer.Hand[0])
+		if cards := g.dealer.Hand().Cards; len(cards) > 1 {
+			dealerInfo = fmt.Sprintf("Dealer: Player: Dealer\nHand: %s, (Hidden card)\nValue: ?\n", cards[0])
+		}
+	}
+
+	seatInfo, scoreInfo := "", ""
+	for _, seat := range g.seats {
+		p := seat.Player
+		seatInfo += fmt.Sprintf("Player: %s\n", p)
+		seatInfo += fmt.Sprintf("Bankroll: %d chips, Bet: %d chips\n", p.Bankroll, seat.Bet())
+		if p.Insurance > 0 {
+			seatInfo += fmt.Sprintf("Insurance: %d chips\n", p.Insurance)
+		}
+
+		// Name each score only when there is more than one to tell apart
+		label := ""
+		if len(g.seats) > 1 {
+			label = " (" + p.Name + ")"
+		}
+		score := seat.Score
+		scoreInfo += fmt.Sprintf("\nSession Score%s - Wins: %d, Losses: %d, Pushes: %d, Surrenders: %d",
+			label, score.Wins, score.Losses, score.Pushes, score.Surrenders)
+		if score.Decisions > 0 {
+			scoreInfo += fmt.Sprintf(", Strategy accuracy: %.0f%% (%d/%d)", score.Accuracy(), score.CorrectDecisions, score.Decisions)
+		}
+		if score.InsuranceWins+score.InsuranceLosses > 0 {
+			scoreInfo += fmt.Sprintf("\nInsurance%s - Won: %d, Lost: %d", label, score.InsuranceWins, score.InsuranceLosses)
 		}
 	}
-	playerInfo := fmt.Sprintf("Player: %s\n", g.player)
-	scoreInfo := fmt.Sprintf("\nSession Score - Wins: %d, Losses: %d, Pushes: %d", g.score.Wins, g.score.Losses, g.score.Pushes)
-	return gameState + dealerInfo + playerInfo + scoreInfo
+	return gameState + dealerInfo + seatInfo + scoreInfo
 }
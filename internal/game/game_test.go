@@ -1,19 +1,25 @@
 package game
 
 import (
+	"blackjack/internal/count"
 	"blackjack/internal/deck"
+	"blackjack/internal/rules"
+	"errors"
 	"strings"
 	"testing"
 )
 
+// testSeed deals an ordinary first round (no naturals), so player actions are always legal
+const testSeed = 12
+
 // TestNewGame tests game creation
 func TestNewGame(t *testing.T) {
-	game := NewGame("Test Player")
+	game := newTestGame(t)
 
 	if game.state != WaitingToStart {
 		t.Errorf("Expected initial state WaitingToStart, got %v", game.state)
 	}
-	if game.player == nil {
+	if game.current() == nil {
 		t.Error("Expected player to be initialized")
 	}
 	if game.dealer == nil {
@@ -24,9 +30,218 @@ func TestNewGame(t *testing.T) {
 	}
 }
 
+// TestWithDeck tests playing from a multi-deck shoe
+func TestWithDeck(t *testing.T) {
+	shoe, err := deck.NewShoe(6)
+	if err != nil {
+		t.Fatalf("Failed to create shoe: %v", err)
+	}
+	game := newTestGame(t, WithDeck(shoe))
+
+	if game.deck != shoe {
+		t.Fatal("Expected game to use the given shoe")
+	}
+	if err := game.StartRound(); err != nil {
+		t.Fatalf("Unexpected error starting round: %v", err)
+	}
+	if shoe.RemainingCards() != 312-4 {
+		t.Errorf("Expected %d cards left in shoe, got %d", 312-4, shoe.RemainingCards())
+	}
+
+	if _, err := NewGame("Test Player", WithDeck(nil)); err == nil {
+		t.Error("Expected error for nil deck")
+	}
+}
+
+// TestCutCardReshuffle tests reshuffling at the end of the round the cut card comes out
+func TestCutCardReshuffle(t *testing.T) {
+	shoe, _ := deck.NewShoe(6)
+	if err := shoe.SetPenetration(80); err != nil {
+		t.Fatalf("Failed to set penetration: %v", err)
+	}
+	game := newTestGame(t, WithDeck(shoe))
+
+	// Play rounds until the cut card comes out
+	rounds := 0
+	for !game.Shuffled() {
+		if err := game.StartRound(); err != nil {
+			t.Fatalf("Unexpected error starting round: %v", err)
+		}
+		if game.state == PlayerTurn {
+			game.PlayerStand()
+		}
+		if game.state == DealerTurn {
+			game.DealerPlay()
+		}
+		rounds++
+
+		if !game.Shuffled() && shoe.CutCardReached() {
+			t.Fatal("Expected a reshuffle once the cut card came out")
+		}
+		if rounds > 200 {
+			t.Fatal("Shoe was never reshuffled")
+		}
+	}
+
+	if shoe.RemainingCards() != 312 || shoe.DeckCount() != 6 {
+		t.Errorf("Expected full 6-deck shoe after reshuffle, got %d cards", shoe.RemainingCards())
+	}
+
+	// The event is cleared when the next round starts
+	game.StartRound()
+	if game.Shuffled() {
+		t.Error("Expected shuffle event to be cleared by the next round")
+	}
+}
+
+// TestWithSeed tests that the same seed always deals the same rounds
+func TestWithSeed(t *testing.T) {
+	deal := func(seed int64) []deck.Card {
+		shoe, _ := deck.NewShoe(2)
+		game := newTestGame(t, WithDeck(shoe), WithSeed(seed))
+
+		// Play enough rounds to pass through at least one reshuffle
+		var cards []deck.Card
+		for i := 0; i < 40; i++ {
+			if err := game.StartRound(); err != nil {
+				t.Fatalf("Unexpected error starting round: %v", err)
+			}
+			if game.state == PlayerTurn {
+				game.PlayerStand()
+			}
+			if game.state == DealerTurn {
+				game.DealerPlay()
+			}
+			cards = append(cards, game.current().Hand().Cards...)
+			cards = append(cards, game.dealer.Hand().Cards...)
+		}
+		return cards
+	}
+
+	first, second, other := deal(42), deal(42), deal(43)
+	if len(first) != len(second) {
+		t.Fatalf("Expected the same number of cards, got %d and %d", len(first), len(second))
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Card %d differs between games with the same seed: %s vs %s", i, first[i], second[i])
+		}
+	}
+
+	same := len(first) == len(other)
+	for i := 0; same && i < len(first); i++ {
+		same = first[i] == other[i]
+	}
+	if same {
+		t.Error("Expected different seeds to deal different rounds")
+	}
+
+	if _, err := NewGame("Test Player", WithSource(nil)); err == nil {
+		t.Error("Expected error for nil random source")
+	}
+}
+
+// TestWithSecureShuffle tests the shuffle commitment published by a secure game
+func TestWithSecureShuffle(t *testing.T) {
+	game := newTestGame(t, WithSecureShuffle())
+	commitment := game.ShuffleCommitment()
+	if commitment == "" {
+		t.Fatal("Expected a shuffle commitment before the deal")
+	}
+	if _, ok := game.RevealShuffle(); ok {
+		t.Error("Expected nothing to reveal before the shoe is finished")
+	}
+
+	// Play until the shoe is reshuffled, then check the reveal
+	for i := 0; !game.Shuffled(); i++ {
+		game.StartRound()
+		if game.state == PlayerTurn {
+			game.PlayerStand()
+		}
+		if game.state == DealerTurn {
+			game.DealerPlay()
+		}
+		if i > 100 {
+			t.Fatal("Shoe was never reshuffled")
+		}
+	}
+	proof, ok := game.RevealShuffle()
+	if !ok || proof.Commitment != commitment || !proof.Verify() {
+		t.Error("Expected the finished shoe's proof to match its commitment")
+	}
+
+	if _, err := NewGame("Test Player", WithSecureShuffle(), WithSeed(1)); err == nil {
+		t.Error("Expected error combining secure shuffling with a seed")
+	}
+}
+
+// TestStackedScenarios tests exact scenarios scripted with a stacked deck
+func TestStackedScenarios(t *testing.T) {
+	t.Run("Dealer BlackJack beats player 21", func(t *testing.T) {
+		// Without a hole card: player 7 7, dealer A, player hits a 7, dealer draws a K
+		table := rules.DefaultTableRules()
+		table.DealerPeeks = false
+		game := newTestGame(t, WithRules(table), WithStackedDeck(mustParseCards(t, "7H AS 7C 7D KS")))
+		game.StartRound()
+		if err := game.PlayerHit(); err != nil {
+			t.Fatalf("Unexpected error hitting: %v", err)
+		}
+		if game.current().GetHandValue() != 21 {
+			t.Fatalf("Expected player 21, got %d", game.current().GetHandValue())
+		}
+		game.PlayerStand()
+		game.DealerPlay()
+
+		if result := game.GetResult(); result != "Dealer has BlackJack! Dealer wins!" {
+			t.Errorf("Unexpected result %q", result)
+		}
+	})
+
+	t.Run("Shoe runs out mid-round", func(t *testing.T) {
+		game := newTestGame(t, WithStackedDeck(mustParseCards(t, "2H TS 3C 6S 4D")))
+		game.StartRound()
+		if err := game.PlayerHit(); err != nil {
+			t.Fatalf("Unexpected error on first hit: %v", err)
+		}
+		if err := game.PlayerHit(); err == nil {
+			t.Error("Expected error hitting from an empty shoe")
+		}
+	})
+
+	if _, err := NewGame("Test Player", WithStackedDeck([]deck.Card{{Suit: "Stars", Rank: deck.Ace}})); err == nil {
+		t.Error("Expected error for invalid stacked card")
+	}
+}
+
+// TestWithRules tests building the shoe from the table rules
+func TestWithRules(t *testing.T) {
+	table := rules.DefaultTableRules()
+	table.Decks = 8
+	table.Penetration = 80
+	game := newTestGame(t, WithRules(table))
+	if game.deck.DeckCount() != 8 || game.deck.Penetration() != 80 {
+		t.Errorf("Expected 8-deck shoe at 80%%, got %d decks at %v%%", game.deck.DeckCount(), game.deck.Penetration())
+	}
+	if game.Rules() != table {
+		t.Errorf("Expected rules %+v, got %+v", table, game.Rules())
+	}
+
+	// A shoe given with WithDeck sets the deck count, whatever the order of options
+	shoe, _ := deck.NewShoe(2)
+	game = newTestGame(t, WithDeck(shoe), WithRules(table))
+	if game.Rules().Decks != 2 || game.deck != shoe {
+		t.Errorf("Expected rules to follow the 2-deck shoe, got %d decks", game.Rules().Decks)
+	}
+
+	table.Decks = 3
+	if _, err := NewGame("Test Player", WithRules(table)); err == nil {
+		t.Error("Expected error for invalid table rules")
+	}
+}
+
 // TestStartRound tests starting a new round
 func TestStartRound(t *testing.T) {
-	game := NewGame("Test Player")
+	game := newTestGame(t)
 
 	err := game.StartRound()
 	if err != nil {
@@ -34,38 +249,49 @@ func TestStartRound(t *testing.T) {
 	}
 
 	// Check initial deal
-	if len(game.player.Hand) != 2 {
+	if len(game.current().Hand().Cards) != 2 {
 		t.Error("Expected player to have 2 cards")
 	}
-	if len(game.dealer.Hand) != 2 {
+	if len(game.dealer.Hand().Cards) != 2 {
 		t.Error("Expected dealer to have 2 cards")
 	}
 
 	// Test state after dealing
-	if game.state != PlayerTurn && game.state != DealerTurn {
-		t.Error("Expected state to be PlayerTurn or DealerTurn (in case of player BlackJack)")
+	if game.state != PlayerTurn && game.state != DealerTurn && game.state != RoundOver {
+		t.Error("Expected state to be PlayerTurn, DealerTurn (in case of player BlackJack) or RoundOver (in case of dealer BlackJack)")
+	}
+
+	// A round in progress cannot be dealt over, which would lose its bets
+	game = newTestGame(t, WithStackedDeck(mustParseCards(t, "TH 9H 7C 8S")))
+	game.PlaceBet(10)
+	game.StartRound()
+	if game.StartRound() == nil {
+		t.Error("Expected error starting a round mid-round")
+	}
+	if game.Bet() != 10 || len(game.current().Hand().Cards) != 2 {
+		t.Errorf("Expected the round in progress to keep its bet of 10, got %d", game.Bet())
 	}
 }
 
 // TestPlayerActions tests player hit and stand actions
 func TestPlayerActions(t *testing.T) {
 	t.Run("Player Hit", func(t *testing.T) {
-		game := NewGame("Test Player")
+		game := newTestGame(t, WithSeed(testSeed))
 		game.StartRound()
 
-		initialCards := len(game.player.Hand)
+		initialCards := len(game.current().Hand().Cards)
 		err := game.PlayerHit()
 
 		if err != nil {
 			t.Errorf("Unexpected error on hit: %v", err)
 		}
-		if len(game.player.Hand) != initialCards+1 {
+		if len(game.current().Hand().Cards) != initialCards+1 {
 			t.Error("Expected player to receive one card")
 		}
 	})
 
 	t.Run("Player Stand", func(t *testing.T) {
-		game := NewGame("Test Player")
+		game := newTestGame(t, WithSeed(testSeed))
 		game.StartRound()
 
 		err := game.PlayerStand()
@@ -78,7 +304,7 @@ func TestPlayerActions(t *testing.T) {
 	})
 
 	t.Run("Cannot Hit After Stand", func(t *testing.T) {
-		game := NewGame("Test Player")
+		game := newTestGame(t, WithSeed(testSeed))
 		game.StartRound()
 		game.PlayerStand()
 
@@ -89,9 +315,403 @@ func TestPlayerActions(t *testing.T) {
 	})
 }
 
+// TestPlayerDouble tests doubling down
+func TestPlayerDouble(t *testing.T) {
+	t.Run("Double wins twice the bet", func(t *testing.T) {
+		// Player 6 5, dealer T 7, player doubles onto a T for 21
+		game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "6H TS 5C 7S TD")))
+		game.PlaceBet(20)
+		game.StartRound()
+		if !game.LegalActions().Double {
+			t.Fatal("Expected double to be legal on 11")
+		}
+		if err := game.PlayerDouble(); err != nil {
+			t.Fatalf("Unexpected error doubling: %v", err)
+		}
+		if len(game.current().Hand().Cards) != 3 || game.state != DealerTurn || !game.current().Hand().Doubled {
+			t.Fatalf("Expected one card and the end of the turn, got %d cards in state %v", len(game.current().Hand().Cards), game.state)
+		}
+		if game.Bet() != 40 || game.Bankroll() != 60 {
+			t.Errorf("Expected bet 40 and bankroll 60, got %d and %d", game.Bet(), game.Bankroll())
+		}
+
+		game.DealerPlay()
+		game.GetResult()
+		if game.RoundNet() != 40 || game.Bankroll() != 140 {
+			t.Errorf("Expected to win 40 for 140 chips, got %d for %d", game.RoundNet(), game.Bankroll())
+		}
+	})
+
+	t.Run("Double bust ends the round", func(t *testing.T) {
+		game := newTestGame(t, WithStackedDeck(mustParseCards(t, "TH TS 4C 7S KD")))
+		game.StartRound()
+		if err := game.PlayerDouble(); err != nil {
+			t.Fatalf("Unexpected error doubling: %v", err)
+		}
+		if game.state != RoundOver {
+			t.Errorf("Expected round over after doubling into a bust, got %v", game.state)
+		}
+	})
+
+	t.Run("Only on the first two cards", func(t *testing.T) {
+		game := newTestGame(t, WithStackedDeck(mustParseCards(t, "2H TS 3C 7S 4D")))
+		game.StartRound()
+		game.PlayerHit()
+		if game.CanDouble() || game.PlayerDouble() == nil {
+			t.Error("Expected double to be refused after hitting")
+		}
+	})
+
+	t.Run("Restricted by table rules", func(t *testing.T) {
+		tests := []struct {
+			rule  rules.DoubleRule
+			cards string
+			legal bool
+		}{
+			{rules.DoubleAnyTwo, "TH TS 2C 7S", true},
+			{rules.DoubleNineToEleven, "TH TS 2C 7S", false},
+			{rules.DoubleNineToEleven, "5H TS 4C 7S", true},
+			{rules.DoubleTenToEleven, "5H TS 4C 7S", false},
+			{rules.DoubleTenToEleven, "5H TS 5C 7S", true},
+		}
+		for _, test := range tests {
+			table := rules.DefaultTableRules()
+			table.Double = test.rule
+			game := newTestGame(t, WithRules(table), WithStackedDeck(mustParseCards(t, test.cards+" 2D")))
+			game.StartRound()
+			if game.CanDouble() != test.legal {
+				t.Errorf("Double %s on %d: expected legal=%t", test.rule, game.current().GetHandValue(), test.legal)
+			}
+		}
+	})
+
+	t.Run("Needs chips to match the bet", func(t *testing.T) {
+		game := newTestGame(t, WithBankroll(30), WithStackedDeck(mustParseCards(t, "6H TS 5C 7S TD")))
+		game.PlaceBet(20)
+		game.StartRound()
+		if err := game.PlayerDouble(); !errors.Is(err, ErrInsufficientFunds) {
+			t.Errorf("Expected insufficient funds, got %v", err)
+		}
+	})
+}
+
+// TestPlayerSplit tests splitting pairs into hands that are played and settled separately
+func TestPlayerSplit(t *testing.T) {
+	t.Run("Each hand settles on its own", func(t *testing.T) {
+		// Player 8 8, dealer T 7; the first hand draws a T, the second a 9
+		game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "8H TS 8C 7S TD 9D")))
+		game.PlaceBet(10)
+		game.StartRound()
+		if !game.LegalActions().Split {
+			t.Fatal("Expected split to be legal on a pair")
+		}
+		if err := game.PlayerSplit(); err != nil {
+			t.Fatalf("Unexpected error splitting: %v", err)
+		}
+		if count, active := game.Hands(); count != 2 || active != 1 {
+			t.Fatalf("Expected to play hand 1 of 2, got %d of %d", active, count)
+		}
+		if game.Bet() != 20 || game.Bankroll() != 80 {
+			t.Errorf("Expected bet 20 and bankroll 80, got %d and %d", game.Bet(), game.Bankroll())
+		}
+		if game.current().GetHandValue() != 18 {
+			t.Errorf("Expected first hand to hold 18, got %d", game.current().GetHandValue())
+		}
+
+		game.PlayerStand()
+		if _, active := game.Hands(); active != 2 || game.current().GetHandValue() != 17 {
+			t.Fatalf("Expected to play hand 2 holding 17, got hand %d with %d", active, game.current().GetHandValue())
+		}
+		game.PlayerStand()
+		if game.state != DealerTurn {
+			t.Fatalf("Expected dealer's turn after the last hand, got %v", game.state)
+		}
+
+		game.DealerPlay()
+		result := game.GetResult()
+		if result != "Hand 1: Player wins!\nHand 2: Push! It's a tie!" {
+			t.Errorf("Unexpected result %q", result)
+		}
+		if game.RoundNet() != 10 || game.Bankroll() != 110 || game.Bet() != 0 {
+			t.Errorf("Expected to win 10 for 110 chips, got %d for %d", game.RoundNet(), game.Bankroll())
+		}
+		if score := game.GetScore(); score.Wins != 1 || score.Pushes != 1 {
+			t.Errorf("Expected one win and one push, got %+v", score)
+		}
+	})
+
+	t.Run("Split Aces get one card and 21 is not a BlackJack", func(t *testing.T) {
+		game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "AH TS AC 7S KD 5D")))
+		game.PlaceBet(10)
+		game.StartRound()
+		if err := game.PlayerSplit(); err != nil {
+			t.Fatalf("Unexpected error splitting: %v", err)
+		}
+		if game.state != DealerTurn {
+			t.Fatalf("Expected both Aces to stand on one card, got %v", game.state)
+		}
+		if game.current().Hands[0].Evaluate().Natural {
+			t.Error("Expected 21 on a split hand not to be a natural")
+		}
+
+		game.DealerPlay()
+		game.GetResult()
+		if game.RoundNet() != 0 {
+			t.Errorf("Expected 21 to win 1:1 and soft 16 to lose, got net %d", game.RoundNet())
+		}
+	})
+
+	t.Run("Resplitting Aces", func(t *testing.T) {
+		cards := "AH TS AC 7S AD 5D 9C 8C"
+		table := rules.DefaultTableRules()
+		game := newTestGame(t, WithRules(table), WithStackedDeck(mustParseCards(t, cards)))
+		game.StartRound()
+		game.PlayerSplit()
+		if game.state != DealerTurn {
+			t.Error("Expected split Aces to stand when they may not be resplit")
+		}
+
+		table.ResplitAces = true
+		game = newTestGame(t, WithRules(table), WithStackedDeck(mustParseCards(t, cards)))
+		game.StartRound()
+		game.PlayerSplit()
+		if !game.CanSplit() || game.CanDouble() || game.PlayerHit() == nil {
+			t.Fatal("Expected split Aces to allow only a resplit or a stand")
+		}
+		if err := game.PlayerSplit(); err != nil {
+			t.Fatalf("Unexpected error resplitting: %v", err)
+		}
+		if count, _ := game.Hands(); count != 3 || game.state != DealerTurn {
+			t.Errorf("Expected three hands of Aces standing, got %d in state %v", count, game.state)
+		}
+	})
+
+	t.Run("Limited by table rules", func(t *testing.T) {
+		cards := "8H TS 8C 7S 8D 3D"
+		table := rules.DefaultTableRules()
+		table.MaxSplits = 1
+		table.DoubleAfterSplit = false
+		game := newTestGame(t, WithRules(table), WithStackedDeck(mustParseCards(t, cards)))
+		game.StartRound()
+		game.PlayerSplit()
+		if game.CanSplit() {
+			t.Error("Expected resplit to be refused after the maximum splits")
+		}
+
+		game.PlayerStand()
+		if game.current().GetHandValue() != 11 || game.CanDouble() {
+			t.Error("Expected double on 11 to be refused after a split without DAS")
+		}
+
+		table.MaxSplits = 0
+		game = newTestGame(t, WithRules(table), WithStackedDeck(mustParseCards(t, cards)))
+		game.StartRound()
+		if game.CanSplit() || game.PlayerSplit() == nil {
+			t.Error("Expected split to be refused when the table allows none")
+		}
+	})
+
+	t.Run("Busting every hand ends the round", func(t *testing.T) {
+		game := newTestGame(t, WithStackedDeck(mustParseCards(t, "8H TS 8C 7S 6D KD 8S KH")))
+		game.StartRound()
+		game.PlayerSplit()
+		game.PlayerHit()
+		if game.state != PlayerTurn {
+			t.Fatalf("Expected to play the second hand after busting the first, got %v", game.state)
+		}
+		game.PlayerHit()
+		if game.state != RoundOver || len(game.dealer.Hand().Cards) != 2 {
+			t.Errorf("Expected round over without the dealer drawing, got %v", game.state)
+		}
+	})
+
+	t.Run("Refused", func(t *testing.T) {
+		game := newTestGame(t, WithStackedDeck(mustParseCards(t, "8H TS 9C 7S")))
+		game.StartRound()
+		if game.CanSplit() || game.PlayerSplit() == nil {
+			t.Error("Expected split to be refused on a non-pair")
+		}
+
+		game = newTestGame(t, WithBankroll(10), WithStackedDeck(mustParseCards(t, "8H TS 8C 7S")))
+		game.PlaceBet(10)
+		game.StartRound()
+		if err := game.PlayerSplit(); !errors.Is(err, ErrInsufficientFunds) {
+			t.Errorf("Expected insufficient funds, got %v", err)
+		}
+	})
+}
+
+// TestPlayerSurrender tests giving up a hand for half the bet
+func TestPlayerSurrender(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   rules.SurrenderRule
+		noPeek bool
+		cards  string
+		net    int
+		score  Score
+		result string
+	}{
+		{"Late surrender returns half", rules.LateSurrender, false, "TH TS 6C 7S", -10, Score{Surrenders: 1, Decisions: 1, CorrectDecisions: 1}, "Player surrendered! Half the bet is returned."},
+		{"Late surrender loses to BlackJack", rules.LateSurrender, true, "TH AS 6C KS", -20, Score{Losses: 1, Decisions: 1, CorrectDecisions: 1}, "Dealer has BlackJack! Late surrender doesn't count, dealer wins!"},
+		{"Early surrender beats BlackJack", rules.EarlySurrender, false, "TH AS 6C KS", -10, Score{Surrenders: 1, Decisions: 1, CorrectDecisions: 1}, "Player surrendered! Half the bet is returned."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := rules.DefaultTableRules()
+			table.Surrender = test.rule
+			table.DealerPeeks = !test.noPeek
+			game := newTestGame(t, WithRules(table), WithBankroll(100), WithStackedDeck(mustParseCards(t, test.cards)))
+			game.PlaceBet(20)
+			game.StartRound()
+			game.DeclineInsurance() // Offered when the dealer shows an Ace
+			if !game.LegalActions().Surrender {
+				t.Fatal("Expected surrender to be legal as the first decision")
+			}
+			if err := game.PlayerSurrender(); err != nil {
+				t.Fatalf("Unexpected error surrendering: %v", err)
+			}
+			if game.state != RoundOver {
+				t.Fatalf("Expected the round to end, got %v", game.state)
+			}
+
+			if result := game.GetResult(); result != test.result {
+				t.Errorf("Expected %q, got %q", test.result, result)
+			}
+			if game.RoundNet() != test.net || game.Bankroll() != 100+test.net {
+				t.Errorf("Expected net %d, got %d with %d chips", test.net, game.RoundNet(), game.Bankroll())
+			}
+			if game.GetScore() != test.score {
+				t.Errorf("Expected score %+v, got %+v", test.score, game.GetScore())
+			}
+		})
+	}
+
+	t.Run("Refused", func(t *testing.T) {
+		game := newTestGame(t, WithStackedDeck(mustParseCards(t, "TH TS 6C 7S 2D")))
+		game.StartRound()
+		if game.CanSurrender() || game.PlayerSurrender() == nil {
+			t.Error("Expected surrender to be refused when the table does not offer it")
+		}
+
+		table := rules.DefaultTableRules()
+		table.Surrender = rules.LateSurrender
+		game = newTestGame(t, WithRules(table), WithStackedDeck(mustParseCards(t, "TH TS 6C 7S 2D")))
+		game.StartRound()
+		game.PlayerHit()
+		if game.CanSurrender() || game.PlayerSurrender() == nil {
+			t.Error("Expected surrender to be refused after hitting")
+		}
+
+		game = newTestGame(t, WithRules(table), WithStackedDeck(mustParseCards(t, "8H TS 8C 7S 2D 3D")))
+		game.StartRound()
+		game.PlayerSplit()
+		if game.CanSurrender() {
+			t.Error("Expected surrender to be refused after splitting")
+		}
+	})
+}
+
+// TestDealerPeek tests the dealer checking for BlackJack before the player acts
+func TestDealerPeek(t *testing.T) {
+	t.Run("BlackJack under a ten ends the round", func(t *testing.T) {
+		game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "9H TS 7C AS")))
+		game.PlaceBet(20)
+		game.StartRound()
+		if game.state != RoundOver {
+			t.Fatalf("Expected the round to end at once, got %v", game.state)
+		}
+		if result := game.GetResult(); result != "Dealer has BlackJack! Dealer wins!" || game.RoundNet() != -20 {
+			t.Errorf("Expected to lose 20 to BlackJack, got %q (net %d)", result, game.RoundNet())
+		}
+	})
+
+	t.Run("Peek follows insurance under an Ace", func(t *testing.T) {
+		game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "9H AS 7C KS")))
+		game.PlaceBet(20)
+		game.StartRound()
+		game.PlaceInsurance(10)
+		if game.state != RoundOver {
+			t.Fatalf("Expected the round to end after insurance, got %v", game.state)
+		}
+		game.GetResult()
+		if game.RoundNet() != 0 {
+			t.Errorf("Expected insurance to cover the loss, got net %d", game.RoundNet())
+		}
+	})
+
+	t.Run("No BlackJack continues the round", func(t *testing.T) {
+		game := newTestGame(t, WithStackedDeck(mustParseCards(t, "9H TS 7C 9S")))
+		game.StartRound()
+		if game.state != PlayerTurn {
+			t.Errorf("Expected player's turn, got %v", game.state)
+		}
+	})
+
+	t.Run("BlackJack against BlackJack pushes", func(t *testing.T) {
+		game := newTestGame(t, WithStackedDeck(mustParseCards(t, "AH TS KC AS")))
+		game.StartRound()
+		if game.state != RoundOver || game.GetResult() != "Push! It's a tie!" {
+			t.Errorf("Expected an immediate push, got %v", game.state)
+		}
+	})
+
+	t.Run("Early surrender comes before the peek", func(t *testing.T) {
+		table := rules.DefaultTableRules()
+		table.Surrender = rules.EarlySurrender
+		game := newTestGame(t, WithRules(table), WithStackedDeck(mustParseCards(t, "9H TS 7C AS 5D")))
+		game.StartRound()
+		if game.state != PlayerTurn || !game.CanSurrender() {
+			t.Fatalf("Expected the chance to surrender first, got %v", game.state)
+		}
+		if err := game.PlayerHit(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if game.state != RoundOver || len(game.current().Hand().Cards) != 2 {
+			t.Errorf("Expected the peek to end the round before the hit, got %v with %d cards", game.state, len(game.current().Hand().Cards))
+		}
+	})
+}
+
+// TestNoHoleCard tests the European no-hole-card game, where the dealer does not peek
+func TestNoHoleCard(t *testing.T) {
+	table := rules.DefaultTableRules()
+	table.DealerPeeks = false
+
+	t.Run("Doubles are lost to a late BlackJack", func(t *testing.T) {
+		game := newTestGame(t, WithRules(table), WithBankroll(100), WithStackedDeck(mustParseCards(t, "6H TS 5C TD AS")))
+		game.PlaceBet(20)
+		game.StartRound()
+		if len(game.dealer.Hand().Cards) != 1 || game.state != PlayerTurn {
+			t.Fatalf("Expected the dealer to hold one card, got %d", len(game.dealer.Hand().Cards))
+		}
+		game.PlayerDouble()
+		game.DealerPlay()
+		if result := game.GetResult(); result != "Dealer has BlackJack! Dealer wins!" || game.RoundNet() != -40 {
+			t.Errorf("Expected to lose the doubled bet, got %q (net %d)", result, game.RoundNet())
+		}
+	})
+
+	t.Run("Hole card dealt when every hand busts", func(t *testing.T) {
+		game := newTestGame(t, WithRules(table), WithBankroll(100), WithStackedDeck(mustParseCards(t, "TH AS 6C KD KS")))
+		game.PlaceBet(20)
+		game.StartRound()
+		game.PlaceInsurance(10)
+		game.PlayerHit()
+		if game.state != RoundOver || len(game.dealer.Hand().Cards) != 2 {
+			t.Fatalf("Expected the round over with the dealer's second card dealt, got %v", game.state)
+		}
+		game.GetResult()
+		if game.RoundNet() != 0 || game.GetScore().InsuranceWins != 1 {
+			t.Errorf("Expected insurance to win against the dealer's BlackJack, got net %d", game.RoundNet())
+		}
+	})
+}
+
 // TestDealerPlay tests dealer's turn
 func TestDealerPlay(t *testing.T) {
-	game := NewGame("Test Player")
+	game := newTestGame(t, WithSeed(testSeed))
 	game.StartRound()
 	game.PlayerStand()
 
@@ -110,6 +730,47 @@ func TestDealerPlay(t *testing.T) {
 	}
 }
 
+// TestDealerSoft17 tests the dealer's soft 17 rule with stacked hands
+func TestDealerSoft17(t *testing.T) {
+	tests := []struct {
+		name        string
+		hitsSoft17  bool
+		cards       string
+		dealerTotal int
+		dealerCards int
+	}{
+		// Player T 9, dealer A 6 (soft 17), next card a 3
+		{"S17 stands on soft 17", false, "TH AS 9C 6S 3D", 17, 2},
+		{"H17 hits soft 17", true, "TH AS 9C 6S 3D", 20, 3},
+		// Player T 9, dealer T 7 (hard 17)
+		{"H17 stands on hard 17", true, "TH TS 9C 7S 3D", 17, 2},
+		// Player T 9, dealer A 6, hits a T for hard 17
+		{"H17 stands once soft 17 turns hard", true, "TH AS 9C 6S TD 3C", 17, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := rules.DefaultTableRules()
+			table.DealerHitsSoft17 = test.hitsSoft17
+			game := newTestGame(t, WithRules(table), WithStackedDeck(mustParseCards(t, test.cards)))
+			game.StartRound()
+			game.PlayerStand()
+			if err := game.DealerPlay(); err != nil {
+				t.Fatalf("Unexpected error during dealer play: %v", err)
+			}
+
+			if game.dealer.GetHandValue() != test.dealerTotal || len(game.dealer.Hand().Cards) != test.dealerCards {
+				t.Errorf("Expected dealer %d with %d cards, got %d with %d cards",
+					test.dealerTotal, test.dealerCards, game.dealer.GetHandValue(), len(game.dealer.Hand().Cards))
+			}
+		})
+	}
+
+	if game := newTestGame(t); game.Rules() != rules.DefaultTableRules() {
+		t.Error("Expected default table rules")
+	}
+}
+
 // TestGetResult tests game result determination
 func TestGetResult(t *testing.T) {
 	tests := []struct {
@@ -121,32 +782,44 @@ func TestGetResult(t *testing.T) {
 			name: "Player Busts",
 			setupGame: func(g *Game) {
 				// Give player cards that will bust
-				g.player.AddCard(mustCreateCard(t, deck.Hearts, deck.King))
-				g.player.AddCard(mustCreateCard(t, deck.Spades, deck.Queen))
-				g.player.AddCard(mustCreateCard(t, deck.Diamonds, deck.Jack))
-				g.state = RoundOver
+				g.current().AddCard(mustCreateCard(t, deck.Hearts, deck.King))
+				g.current().AddCard(mustCreateCard(t, deck.Spades, deck.Queen))
+				g.current().AddCard(mustCreateCard(t, deck.Diamonds, deck.Jack))
+				g.endRound()
 			},
 			expectedResult: "Player busted",
 		},
 		{
 			name: "Player BlackJack",
 			setupGame: func(g *Game) {
-				g.player.AddCard(mustCreateCard(t, deck.Hearts, deck.Ace))
-				g.player.AddCard(mustCreateCard(t, deck.Spades, deck.King))
+				g.current().AddCard(mustCreateCard(t, deck.Hearts, deck.Ace))
+				g.current().AddCard(mustCreateCard(t, deck.Spades, deck.King))
 				g.dealer.AddCard(mustCreateCard(t, deck.Hearts, deck.Ten))
 				g.dealer.AddCard(mustCreateCard(t, deck.Spades, deck.Nine))
-				g.state = RoundOver
+				g.endRound()
 			},
 			expectedResult: "BlackJack",
 		},
+		{
+			name: "Player BlackJack against a dealer bust",
+			setupGame: func(g *Game) {
+				g.current().AddCard(mustCreateCard(t, deck.Hearts, deck.Ace))
+				g.current().AddCard(mustCreateCard(t, deck.Spades, deck.King))
+				g.dealer.AddCard(mustCreateCard(t, deck.Hearts, deck.Ten))
+				g.dealer.AddCard(mustCreateCard(t, deck.Spades, deck.Six))
+				g.dealer.AddCard(mustCreateCard(t, deck.Clubs, deck.Nine))
+				g.endRound()
+			},
+			expectedResult: "BlackJack! Player wins!",
+		},
 		{
 			name: "Push",
 			setupGame: func(g *Game) {
-				g.player.AddCard(mustCreateCard(t, deck.Hearts, deck.Ten))
-				g.player.AddCard(mustCreateCard(t, deck.Spades, deck.Nine))
+				g.current().AddCard(mustCreateCard(t, deck.Hearts, deck.Ten))
+				g.current().AddCard(mustCreateCard(t, deck.Spades, deck.Nine))
 				g.dealer.AddCard(mustCreateCard(t, deck.Diamonds, deck.Ten))
 				g.dealer.AddCard(mustCreateCard(t, deck.Clubs, deck.Nine))
-				g.state = RoundOver
+				g.endRound()
 			},
 			expectedResult: "Push",
 		},
@@ -154,7 +827,7 @@ func TestGetResult(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			game := NewGame("Test Player")
+			game := newTestGame(t)
 			test.setupGame(game)
 
 			result := game.GetResult()
@@ -167,7 +840,7 @@ func TestGetResult(t *testing.T) {
 
 // TestString tests game state string representation
 func TestString(t *testing.T) {
-	game := NewGame("Test Player")
+	game := newTestGame(t, WithSeed(testSeed))
 	game.StartRound()
 
 	// During play, dealer's second card should be hidden
@@ -252,16 +925,16 @@ func TestScoreTracking(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			g := NewGame("TestPlayer")
-			g.state = RoundOver
+			g := newTestGame(t)
 
-			// Set up player and dealer hands
+			// Set up player and dealer hands, then end the round
 			for _, card := range tt.playerCards {
-				g.player.AddCard(card)
+				g.current().AddCard(card)
 			}
 			for _, card := range tt.dealerCards {
 				g.dealer.AddCard(card)
 			}
+			g.endRound()
 
 			// Get result and check score
 			result := g.GetResult()
@@ -278,11 +951,11 @@ func TestScoreTracking(t *testing.T) {
 }
 
 func TestScoreDisplay(t *testing.T) {
-	g := NewGame("TestPlayer")
-	g.score = Score{Wins: 2, Losses: 1, Pushes: 1}
+	g := newTestGame(t)
+	g.seats[0].Score = Score{Wins: 2, Losses: 1, Pushes: 1}
 
 	output := g.String()
-	expectedScore := "Session Score - Wins: 2, Losses: 1, Pushes: 1"
+	expectedScore := "Session Score - Wins: 2, Losses: 1, Pushes: 1, Surrenders: 0"
 	if !strings.Contains(output, expectedScore) {
 		t.Errorf("Score not displayed correctly in game state.\nExpected to contain: %s\nGot: %s", expectedScore, output)
 	}
@@ -292,6 +965,83 @@ func TestScoreDisplay(t *testing.T) {
 	}
 }
 
+// TestCounter tests counting the cards seen during a round
+func TestCounter(t *testing.T) {
+	// Player 9, 9 against a dealer six with a ten in the hole, then the dealer draws an Ace
+	game := newTestGame(t, WithStackedDeck(mustParseCards(t, "9H 6S 9C TS AD")))
+	game.StartRound()
+	if got := game.Counter().Running(); got != 1 {
+		t.Errorf("Expected the hole card to stay out of the count, got %+g", got)
+	}
+
+	game.PlayerStand()
+	game.DealerPlay()
+	if got := game.Counter().Running(); got != -1 || game.Counter().Seen() != 5 {
+		t.Errorf("Expected a count of -1 from 5 cards once the hole card is turned over, got %v", game.Counter())
+	}
+
+	// Other systems can be chosen when the game is created
+	game = newTestGame(t, WithCountingSystem(count.KO), WithStackedDeck(mustParseCards(t, "7H 6S 9C TS")))
+	game.StartRound()
+	if got := game.Counter().Running(); got != 2 || game.Counter().System.Name != "KO" {
+		t.Errorf("Expected a KO count of +2, got %v", game.Counter())
+	}
+	if _, err := NewGame("Test Player", WithCountingSystem(count.System{})); err == nil {
+		t.Error("Expected error for a counting system without a name")
+	}
+}
+
+// TestCounterReshuffle tests that the count starts again with a new shoe
+func TestCounterReshuffle(t *testing.T) {
+	game := newTestGame(t, WithSeed(1))
+	for {
+		game.PlaceBet(10)
+		if err := game.StartRound(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for game.RoundInProgress() {
+			switch game.GetState() {
+			case InsuranceOffered:
+				game.DeclineInsurance()
+			case DealerTurn:
+				game.DealerPlay()
+			default:
+				game.PlayerStand()
+			}
+		}
+		game.GetResult()
+		if game.Shuffled() {
+			break
+		}
+		if seen := game.Counter().Seen(); seen != game.deck.DealtCards() {
+			t.Fatalf("Expected every dealt card to be counted, got %d of %d", seen, game.deck.DealtCards())
+		}
+	}
+	if game.Counter().Seen() != 0 || game.Counter().Running() != 0 {
+		t.Errorf("Expected a fresh count after the reshuffle, got %v", game.Counter())
+	}
+}
+
+// newTestGame creates a game for testing and fails the test on error
+func newTestGame(t *testing.T, opts ...Option) *Game {
+	t.Helper()
+	g, err := NewGame("Test Player", opts...)
+	if err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	return g
+}
+
+// mustParseCards parses a card list such as "AS KH 7D" for testing
+func mustParseCards(t *testing.T, s string) []deck.Card {
+	t.Helper()
+	cards, err := deck.ParseCards(s)
+	if err != nil {
+		t.Fatalf("Failed to parse cards: %v", err)
+	}
+	return cards
+}
+
 // Helper function to create cards for testing
 func mustCreateCard(t *testing.T, suit deck.Suit, rank deck.Rank) deck.Card {
 	card, err := deck.NewCard(suit, rank)
@@ -300,4 +1050,3 @@ func mustCreateCard(t *testing.T, suit deck.Suit, rank deck.Rank) deck.Card {
 	}
 	return card
 }
- 
\ No newline at end of file
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"blackjack/internal/player"
+	"fmt"
+)
+
+// nextInsurance offers insurance to the next seat with a bet when the dealer
+// shows an Ace. Once every seat has decided, play begins.
+func (g *Game) nextInsurance() error {
+	if g.dealer.Hand().Cards[0].IsAce() {
+		for g.turn+1 < len(g.seats) {
+			g.turn++
+			if g.current().Hand().Bet > 0 {
+				g.state = InsuranceOffered
+				return nil
+			}
+		}
+	}
+	return g.beginPlay()
+}
+
+// MaxInsurance returns the largest insurance bet the player whose turn it is
+// may place: half their wager
+func (g *Game) MaxInsurance() int {
+	return g.current().Hand().Bet / 2
+}
+
+// EvenMoneyOffered reports whether the player whose turn it is may take even
+// money: a BlackJack against a dealer Ace, paid 1:1 at once instead of risking a push
+func (g *Game) EvenMoneyOffered() bool {
+	return g.state == InsuranceOffered && g.current().HasBlackjack()
+}
+
+// PlaceInsurance places a side bet of up to half the wager that the dealer has
+// BlackJack. It pays 2:1 when the dealer's hole card is revealed.
+func (g *Game) PlaceInsurance(amount int) error {
+	p := g.current()
+	switch {
+	case g.state != InsuranceOffered:
+		return fmt.Errorf("cannot insure: insurance is not on offer")
+	case amount < 1 || amount > g.MaxInsurance():
+		return fmt.Errorf("cannot insure: bet must be from 1 to %d chips", g.MaxInsurance())
+	case amount > p.Bankroll:
+		return fmt.Errorf("cannot insure: %w", ErrInsufficientFunds)
+	}
+
+	p.Bankroll -= amount
+	p.Insurance = amount
+	return g.nextInsurance()
+}
+
+// DeclineInsurance turns down insurance (and even money) and continues the round
+func (g *Game) DeclineInsurance() error {
+	if g.state != InsuranceOffered {
+		return fmt.Errorf("cannot decline insurance: insurance is not on offer")
+	}
+	return g.nextInsurance()
+}
+
+// TakeEvenMoney accepts even money on a BlackJack, settling the hand as a 1:1 win
+func (g *Game) TakeEvenMoney() error {
+	if !g.EvenMoneyOffered() {
+		return fmt.Errorf("cannot take even money: only offered on a BlackJack against a dealer Ace")
+	}
+	g.current().EvenMoney = true
+	g.current().Stand()
+	return g.nextInsurance()
+}
+
+// settleInsurance pays or collects a seat's insurance bet against the dealer's hand
+func (g *Game) settleInsurance(seat *Seat, dealerValue player.HandValue) string {
+	p := seat.Player
+	insurance := p.Insurance
+	p.Insurance = 0
+
+	if dealerValue.Natural {
+		seat.Score.InsuranceWins++
+		p.Bankroll += 3 * insurance // The side bet back, plus 2:1
+		seat.RoundNet += 2 * insurance
+		return fmt.Sprintf("Dealer has BlackJack! Insurance pays %d chips.", 2*insurance)
+	}
+	seat.Score.InsuranceLosses++
+	seat.RoundNet -= insurance
+	return fmt.Sprintf("Dealer has no BlackJack. Insurance of %d chips lost.", insurance)
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestInsuranceOffered tests when the insurance phase starts
+func TestInsuranceOffered(t *testing.T) {
+	game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "TH AS 6C 7S")))
+	game.PlaceBet(20)
+	game.StartRound()
+	if game.GetState() != InsuranceOffered || !game.RoundInProgress() {
+		t.Fatalf("Expected insurance on a dealer Ace, got %v", game.GetState())
+	}
+	if game.MaxInsurance() != 10 || game.EvenMoneyOffered() {
+		t.Errorf("Expected up to 10 chips of insurance and no even money, got %d", game.MaxInsurance())
+	}
+	if game.PlayerHit() == nil || game.PlaceBet(20) == nil {
+		t.Error("Expected play and betting to wait for the insurance decision")
+	}
+	if err := game.DeclineInsurance(); err != nil || game.GetState() != PlayerTurn {
+		t.Errorf("Expected player's turn after declining, got %v (%v)", game.GetState(), err)
+	}
+
+	// Unstaked rounds have nothing to insure
+	game = newTestGame(t, WithStackedDeck(mustParseCards(t, "TH AS 6C 7S")))
+	game.StartRound()
+	if game.GetState() != PlayerTurn {
+		t.Errorf("Expected no insurance without a bet, got %v", game.GetState())
+	}
+}
+
+// TestPlaceInsurance tests insurance bets against each dealer hand
+func TestPlaceInsurance(t *testing.T) {
+	tests := []struct {
+		name   string
+		cards  string
+		net    int
+		score  Score
+		result string
+	}{
+		{"Pays 2:1 against BlackJack", "TH AS 6C KS", 0, Score{Losses: 1, InsuranceWins: 1}, "Insurance pays 20 chips"},
+		{"Lost without BlackJack", "TH AS 9C 7S", 10, Score{Wins: 1, InsuranceLosses: 1, Decisions: 1, CorrectDecisions: 1}, "Insurance of 10 chips lost"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, test.cards)))
+			game.PlaceBet(20)
+			game.StartRound()
+			if err := game.PlaceInsurance(10); err != nil {
+				t.Fatalf("Unexpected error insuring: %v", err)
+			}
+			// A dealer BlackJack ends and settles the round as soon as the insurance is placed
+			if game.RoundInProgress() && (game.Bet() != 30 || game.Bankroll() != 70) {
+				t.Errorf("Expected 30 on the table and 70 left, got %d and %d", game.Bet(), game.Bankroll())
+			}
+
+			game.PlayerStand()
+			game.DealerPlay()
+			if result := game.GetResult(); !strings.Contains(result, test.result) {
+				t.Errorf("Expected result to contain %q, got %q", test.result, result)
+			}
+			if game.RoundNet() != test.net || game.Bankroll() != 100+test.net || game.Bet() != 0 {
+				t.Errorf("Expected net %d, got %d with %d chips", test.net, game.RoundNet(), game.Bankroll())
+			}
+			if game.GetScore() != test.score {
+				t.Errorf("Expected score %+v, got %+v", test.score, game.GetScore())
+			}
+		})
+	}
+
+	t.Run("Limits", func(t *testing.T) {
+		game := newTestGame(t, WithBankroll(25), WithStackedDeck(mustParseCards(t, "TH AS 6C 7S")))
+		game.PlaceBet(20)
+		game.StartRound()
+		for _, amount := range []int{0, 11} {
+			if game.PlaceInsurance(amount) == nil {
+				t.Errorf("Expected insurance of %d to be refused", amount)
+			}
+		}
+		if err := game.PlaceInsurance(10); !errors.Is(err, ErrInsufficientFunds) {
+			t.Errorf("Expected insufficient funds, got %v", err)
+		}
+	})
+}
+
+// TestEvenMoney tests taking even money on a BlackJack against a dealer Ace
+func TestEvenMoney(t *testing.T) {
+	game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "AH AS KC KS")))
+	game.PlaceBet(20)
+	game.StartRound()
+	if !game.EvenMoneyOffered() {
+		t.Fatal("Expected even money to be offered on a BlackJack")
+	}
+	if err := game.TakeEvenMoney(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if game.GetState() != RoundOver {
+		t.Fatalf("Expected the round to end, got %v", game.GetState())
+	}
+	if result := game.GetResult(); result != "Even money! Player wins!" {
+		t.Errorf("Unexpected result %q", result)
+	}
+	if game.RoundNet() != 20 || game.Bankroll() != 120 {
+		t.Errorf("Expected to win 20 for 120 chips, got %d for %d", game.RoundNet(), game.Bankroll())
+	}
+
+	// Declining leaves the BlackJack to be paid in full once the dealer shows no BlackJack
+	game = newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "AH AS KC 7S")))
+	game.PlaceBet(20)
+	game.StartRound()
+	game.DeclineInsurance()
+	if game.GetState() != RoundOver {
+		t.Fatalf("Expected the round to end after declining with a BlackJack, got %v", game.GetState())
+	}
+	if result := game.GetResult(); result != "BlackJack! Player wins!" || game.RoundNet() != 30 {
+		t.Errorf("Expected a 3:2 BlackJack win of 30, got %q for %d", result, game.RoundNet())
+	}
+
+	game = newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "TH AS 6C 7S")))
+	game.PlaceBet(20)
+	game.StartRound()
+	if game.TakeEvenMoney() == nil {
+		t.Error("Expected even money to be refused without a BlackJack")
+	}
+}
@@ -0,0 +1,117 @@
+package game
+
+import (
+	"blackjack/internal/player"
+	"fmt"
+)
+
+// MaxSeats is the number of players a table can seat
+const MaxSeats = 7
+
+// Seat is one place at the table: the player sitting there and their results
+type Seat struct {
+	Player   *player.Player `json:"player"`
+	Score    Score          `json:"score"`              // Results of every round played from this seat
+	RoundNet int            `json:"roundNet,omitempty"` // Chips won or lost in the last settled round
+}
+
+// Seats returns the occupied seats in turn order
+func (g *Game) Seats() []*Seat {
+	return g.seats
+}
+
+// Turn returns the index of the seat whose turn it is
+func (g *Game) Turn() int {
+	return g.turn
+}
+
+// current returns the player whose turn it is
+func (g *Game) current() *player.Player {
+	return g.seats[g.turn].Player
+}
+
+// SitDown seats a new player with the given bankroll at the end of the turn order
+// Players may only join between rounds, and names must be unique at the table
+func (g *Game) SitDown(name string, bankroll int) error {
+	switch {
+	case g.RoundInProgress():
+		return fmt.Errorf("cannot sit down: round in progress")
+	case len(g.seats) >= MaxSeats:
+		return fmt.Errorf("cannot sit down: all %d seats are taken", MaxSeats)
+	case g.seatOf(name) >= 0:
+		return fmt.Errorf("cannot sit down: %q is already at the table", name)
+	case bankroll < 0:
+		return fmt.Errorf("bankroll cannot be negative: %d", bankroll)
+	}
+
+	p := player.NewPlayer(name)
+	p.Bankroll = bankroll
+	g.seats = append(g.seats, &Seat{Player: p})
+	return nil
+}
+
+// Leave removes the named player from the table between rounds
+// Any bet they placed on the next round goes with them. The last player
+// cannot leave, as a game always has someone at the table.
+func (g *Game) Leave(name string) error {
+	if g.RoundInProgress() {
+		return fmt.Errorf("cannot leave: round in progress")
+	}
+	i := g.seatOf(name)
+	switch {
+	case i < 0:
+		return fmt.Errorf("cannot leave: %q is not at the table", name)
+	case len(g.seats) == 1:
+		return fmt.Errorf("cannot leave: %q is the last player at the table", name)
+	}
+
+	g.seats = append(g.seats[:i], g.seats[i+1:]...)
+	g.turn = 0
+	return nil
+}
+
+// seatOf returns the index of the named player's seat, or -1 if they are not seated
+func (g *Game) seatOf(name string) int {
+	for i, seat := range g.seats {
+		if seat.Player.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextSeat hands the turn to the next seat with a hand to play. Once every
+// seat is done the dealer plays, or the round ends if no hand is left for the
+// dealer to beat. A BlackJack is paid without the dealer drawing.
+func (g *Game) nextSeat() error {
+	for g.turn+1 < len(g.seats) {
+		g.turn++
+		if g.current().Hand().State == player.Playing {
+			g.state = PlayerTurn
+			return nil
+		}
+	}
+
+	// Every seat has now had its chance at early surrender
+	if g.peekPending && g.peek() {
+		return nil
+	}
+
+	for _, seat := range g.seats {
+		if seat.Player.EvenMoney {
+			continue
+		}
+		for _, hand := range seat.Player.Hands {
+			value := hand.Evaluate()
+			if !value.Busted && !value.Natural && hand.State != player.Surrendered {
+				g.state = DealerTurn
+				return nil
+			}
+		}
+	}
+	if err := g.dealHoleCard(); err != nil {
+		return err
+	}
+	g.endRound()
+	return nil
+}
@@ -0,0 +1,257 @@
+package game
+
+import (
+	"blackjack/internal/rules"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// TestSitDownAndLeave tests joining and leaving the table between rounds
+func TestSitDownAndLeave(t *testing.T) {
+	game := newTestGame(t, WithSeed(testSeed))
+	for i := 2; i <= MaxSeats; i++ {
+		if err := game.SitDown(fmt.Sprintf("Player %d", i), 100); err != nil {
+			t.Fatalf("Unexpected error seating player %d: %v", i, err)
+		}
+	}
+	if len(game.Seats()) != MaxSeats {
+		t.Fatalf("Expected %d seats, got %d", MaxSeats, len(game.Seats()))
+	}
+	if game.SitDown("One Too Many", 100) == nil {
+		t.Error("Expected error when the table is full")
+	}
+
+	if err := game.Leave("Player 3"); err != nil {
+		t.Fatalf("Unexpected error leaving: %v", err)
+	}
+	if len(game.Seats()) != MaxSeats-1 || game.Seats()[2].Player.Name != "Player 4" {
+		t.Error("Expected the later seats to move up the turn order")
+	}
+	if game.Leave("Player 3") == nil {
+		t.Error("Expected error when the player is not at the table")
+	}
+	if game.SitDown("Player 2", 100) == nil {
+		t.Error("Expected error for a name already at the table")
+	}
+	if game.SitDown("Broke", -1) == nil {
+		t.Error("Expected error for a negative bankroll")
+	}
+
+	game.StartRound()
+	if game.SitDown("Latecomer", 100) == nil || game.Leave("Player 2") == nil {
+		t.Error("Expected the table to be closed mid-round")
+	}
+
+	alone := newTestGame(t)
+	if alone.Leave("Test Player") == nil || len(alone.Seats()) != 1 {
+		t.Error("Expected the last player to be kept at the table")
+	}
+}
+
+// TestMultiSeatRound tests a round played by two seats in turn
+func TestMultiSeatRound(t *testing.T) {
+	// Test Player T 7, Bob 9 9, dealer T 8; Test Player hits a 5
+	game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "TH 9H TS 7C 9C 8S 5D")))
+	game.SitDown("Bob", 100)
+	game.PlaceSeatBet(0, 10)
+	game.PlaceSeatBet(1, 20)
+	if err := game.PlaceSeatBet(2, 10); err == nil {
+		t.Error("Expected error betting for an empty seat")
+	}
+
+	game.StartRound()
+	if game.Turn() != 0 || game.current().GetHandValue() != 17 {
+		t.Fatalf("Expected the first seat to act on 17, got seat %d", game.Turn())
+	}
+	game.PlayerHit()
+	if game.Turn() != 1 || game.state != PlayerTurn {
+		t.Fatalf("Expected the turn to pass to Bob after the bust, got seat %d in %v", game.Turn(), game.state)
+	}
+	game.PlayerStand()
+	if game.state != DealerTurn {
+		t.Fatalf("Expected the dealer to play after the last seat, got %v", game.state)
+	}
+
+	game.DealerPlay()
+	if result := game.GetResult(); result != "Test Player: Player busted! Dealer wins!\nBob: Push! It's a tie!" {
+		t.Errorf("Unexpected result %q", result)
+	}
+	seats := game.Seats()
+	if seats[0].RoundNet != -10 || seats[1].RoundNet != 0 || seats[1].Player.Bankroll != 100 {
+		t.Errorf("Expected nets of -10 and 0, got %d and %d", seats[0].RoundNet, seats[1].RoundNet)
+	}
+	if seats[0].Score.Losses != 1 || seats[1].Score.Pushes != 1 {
+		t.Errorf("Expected each seat to keep its own score, got %+v and %+v", seats[0].Score, seats[1].Score)
+	}
+}
+
+// TestMultiSeatEarlySurrender tests that every seat gets its chance at early
+// surrender before the dealer checks for BlackJack, whatever the others do
+func TestMultiSeatEarlySurrender(t *testing.T) {
+	// Test Player 9 7, Bob 8 3, dealer K with an Ace in the hole
+	table := rules.DefaultTableRules()
+	table.Surrender = rules.EarlySurrender
+	cards := "9H 8C KS 7D 3H AS 5C 6D"
+
+	tests := []struct {
+		name     string
+		first    func(g *Game) error
+		second   func(g *Game) error
+		expected [2]int // Each seat's net for the round
+	}{
+		{"Surrender, then double", (*Game).PlayerSurrender, (*Game).PlayerDouble, [2]int{-5, -10}},
+		{"Stand, then surrender", (*Game).PlayerStand, (*Game).PlayerSurrender, [2]int{-10, -5}},
+		{"Double, then stand", (*Game).PlayerDouble, (*Game).PlayerStand, [2]int{-10, -10}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game := newTestGame(t, WithRules(table), WithBankroll(100), WithStackedDeck(mustParseCards(t, cards)))
+			game.SitDown("Bob", 100)
+			game.PlaceSeatBet(0, 10)
+			game.PlaceSeatBet(1, 10)
+			if err := game.StartRound(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if err := test.first(game); err != nil {
+				t.Fatalf("Unexpected error on the first seat: %v", err)
+			}
+			if game.GetState() != PlayerTurn || game.Turn() != 1 {
+				t.Fatalf("Expected the second seat to get its chance to surrender, got %v at seat %d", game.GetState(), game.Turn())
+			}
+			if err := test.second(game); err != nil {
+				t.Fatalf("Unexpected error on the second seat: %v", err)
+			}
+			if game.GetState() != RoundOver {
+				t.Fatalf("Expected the dealer's BlackJack to end the round, got %v", game.GetState())
+			}
+
+			game.GetResult()
+			for i, seat := range game.Seats() {
+				if seat.RoundNet != test.expected[i] {
+					t.Errorf("Seat %d: expected a net of %d, got %d", i, test.expected[i], seat.RoundNet)
+				}
+			}
+		})
+	}
+}
+
+// TestMultiSeatDeal tests BlackJacks and insurance at a table with several seats
+func TestMultiSeatDeal(t *testing.T) {
+	t.Run("BlackJack seats are skipped", func(t *testing.T) {
+		game := newTestGame(t, WithStackedDeck(mustParseCards(t, "AH 9H TS KC 9C 8S")))
+		game.SitDown("Bob", 100)
+		game.StartRound()
+		if game.Turn() != 1 {
+			t.Errorf("Expected Bob to act first, got seat %d", game.Turn())
+		}
+	})
+
+	t.Run("Insurance is offered to each seat in turn", func(t *testing.T) {
+		game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "TH 9H AS 7C 9C KS")))
+		game.SitDown("Bob", 100)
+		game.PlaceSeatBet(0, 10)
+		game.PlaceSeatBet(1, 20)
+		game.StartRound()
+
+		if game.state != InsuranceOffered || game.Turn() != 0 {
+			t.Fatalf("Expected insurance for the first seat, got %v at seat %d", game.state, game.Turn())
+		}
+		game.PlaceInsurance(5)
+		if game.state != InsuranceOffered || game.Turn() != 1 {
+			t.Fatalf("Expected insurance for Bob, got %v at seat %d", game.state, game.Turn())
+		}
+		game.DeclineInsurance()
+		if game.state != RoundOver {
+			t.Fatalf("Expected the dealer's BlackJack to end the round, got %v", game.state)
+		}
+
+		game.GetResult()
+		if seats := game.Seats(); seats[0].RoundNet != 0 || seats[1].RoundNet != -20 {
+			t.Errorf("Expected nets of 0 and -20, got %d and %d", seats[0].RoundNet, seats[1].RoundNet)
+		}
+	})
+}
+
+// TestFullTableShoe tests that a full table never runs a single deck dry
+func TestFullTableShoe(t *testing.T) {
+	table := rules.DefaultTableRules()
+	table.Decks = 1
+	game := newTestGame(t, WithRules(table), WithSeed(testSeed), WithBankroll(100000))
+	for i := 2; i <= MaxSeats; i++ {
+		game.SitDown(fmt.Sprintf("Player %d", i), 100000)
+	}
+
+	for round := 1; round <= 200; round++ {
+		for i := range game.Seats() {
+			game.PlaceSeatBet(i, 10)
+		}
+		if err := game.StartRound(); err != nil {
+			t.Fatalf("Round %d: unexpected error starting round: %v", round, err)
+		}
+		for game.GetState() != RoundOver {
+			var err error
+			switch game.GetState() {
+			case InsuranceOffered:
+				err = game.DeclineInsurance()
+			case PlayerTurn:
+				err = game.PlayerHit() // Hitting to the end draws as many cards as a round can take
+			case DealerTurn:
+				err = game.DealerPlay()
+			}
+			if err != nil {
+				t.Fatalf("Round %d: unexpected error: %v", round, err)
+			}
+		}
+		game.GetResult()
+	}
+}
+
+// TestFailedDeal tests that a round which cannot be dealt gives the bets back
+func TestFailedDeal(t *testing.T) {
+	game := newTestGame(t, WithBankroll(100), WithStackedDeck(mustParseCards(t, "TH 9H TS")))
+	game.SitDown("Bob", 100)
+	game.PlaceSeatBet(0, 10)
+	game.PlaceSeatBet(1, 20)
+
+	if game.StartRound() == nil {
+		t.Fatal("Expected error dealing from a deck too short for the table")
+	}
+	if game.RoundInProgress() {
+		t.Error("Expected no round to be in progress")
+	}
+	for i, expected := range []int{10, 20} {
+		seat := game.Seats()[i]
+		if seat.Bet() != expected || seat.Player.Bankroll+seat.Bet() != 100 {
+			t.Errorf("Seat %d: expected the bet of %d back, got a bet of %d and %d chips", i, expected, seat.Bet(), seat.Player.Bankroll)
+		}
+		if len(seat.Player.Hand().Cards) != 0 {
+			t.Errorf("Seat %d: expected no cards after the failed deal", i)
+		}
+	}
+}
+
+// TestMultiSeatJSON tests saving and restoring a table with several seats
+func TestMultiSeatJSON(t *testing.T) {
+	original := newTestGame(t, WithSeed(testSeed))
+	original.SitDown("Bob", 250)
+	original.StartRound()
+	original.PlayerStand()
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var restored Game
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(restored.Seats()) != 2 || restored.Turn() != original.Turn() || restored.Seats()[1].Player.Bankroll != 250 {
+		t.Errorf("Expected both seats and the turn restored, got %d seats at turn %d", len(restored.Seats()), restored.Turn())
+	}
+	if restored.String() != original.String() {
+		t.Errorf("Restored game differs:\n%s\nvs\n%s", restored.String(), original.String())
+	}
+}
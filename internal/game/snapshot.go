@@ -0,0 +1,193 @@
+package game
+
+import (
+	"blackjack/internal/deck"
+	"blackjack/internal/player"
+	"blackjack/internal/rules"
+	"encoding/json"
+	"fmt"
+)
+
+// gameStateNames holds the names used when printing and saving game states
+var gameStateNames = map[GameState]string{
+	WaitingToStart: "WaitingToStart",
+	PlayerTurn:     "PlayerTurn",
+	DealerTurn:     "DealerTurn",
+	RoundOver:      "RoundOver",
+
+	InsuranceOffered: "InsuranceOffered",
+}
+
+// String returns the name of the game state
+func (s GameState) String() string {
+	if name, ok := gameStateNames[s]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// MarshalText encodes the state by name (e.g., "PlayerTurn")
+func (s GameState) MarshalText() ([]byte, error) {
+	if _, ok := gameStateNames[s]; !ok {
+		return nil, fmt.Errorf("invalid game state: %d", int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a state name written by MarshalText
+func (s *GameState) UnmarshalText(text []byte) error {
+	for state, name := range gameStateNames {
+		if name == string(text) {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid game state: %q", text)
+}
+
+// Snapshot is the complete state of a game: the shoe in dealing order,
+// every seat's hands and session score, the dealer's hand, the game state
+// and the table rules
+type Snapshot struct {
+	Seats    []*Seat          `json:"seats"`
+	Turn     int              `json:"turn,omitempty"` // Index of the seat whose turn it is
+	Dealer   *player.Player   `json:"dealer"`
+	Deck     *deck.Deck       `json:"deck"`
+	State    GameState        `json:"state"`
+	Rules    rules.TableRules `json:"rules"`
+	Shuffled bool             `json:"shuffled,omitempty"`
+	Result   string           `json:"result,omitempty"` // Outcome of the last round, once it has ended
+
+	PeekPending bool `json:"peekPending,omitempty"` // The dealer has yet to check for BlackJack
+}
+
+// Snapshot captures the current state of the game
+// The snapshot shares the game's seats, dealer and deck, so marshal it before playing on
+func (g *Game) Snapshot() Snapshot {
+	return Snapshot{
+		Seats:    g.seats,
+		Turn:     g.turn,
+		Dealer:   g.dealer,
+		Deck:     g.deck,
+		State:    g.state,
+		Rules:    g.rules,
+		Shuffled: g.shuffled,
+		Result:   g.result,
+
+		PeekPending: g.peekPending,
+	}
+}
+
+// FromSnapshot creates a game that continues exactly where the snapshot left off
+// Options such as WithSeed apply to future shuffles of the restored shoe;
+// the snapshot's seats, rules and shoe replace anything other options set up
+func FromSnapshot(s Snapshot, opts ...Option) (*Game, error) {
+	if s.Dealer == nil || s.Deck == nil {
+		return nil, fmt.Errorf("incomplete snapshot: dealer and deck are required")
+	}
+	if err := validateSeats(s.Seats, s.Turn); err != nil {
+		return nil, err
+	}
+
+	if err := s.Rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid table rules: %v", err)
+	}
+	if _, ok := gameStateNames[s.State]; !ok {
+		return nil, fmt.Errorf("invalid game state: %d", int(s.State))
+	}
+	if err := validateHands(s.Dealer); err != nil {
+		return nil, err
+	}
+
+	g := &Game{}
+	for _, opt := range opts {
+		if err := opt(g); err != nil {
+			return nil, fmt.Errorf("invalid game option: %v", err)
+		}
+	}
+
+	// The snapshot's hands and shoe replace anything the options set up
+	g.seats = s.Seats
+	g.turn = s.Turn
+	g.dealer = s.Dealer
+	g.deck = s.Deck
+	g.state = s.State
+	g.rules = s.Rules
+	g.shuffled = s.Shuffled
+	g.result = s.Result
+	g.peekPending = s.PeekPending
+	if err := g.configureShuffle(); err != nil {
+		return nil, err
+	}
+	g.recount()
+	return g, nil
+}
+
+// recount rebuilds the count from the cards dealt since the shoe was shuffled,
+// leaving out the dealer's hole card while it is still face down
+func (g *Game) recount() {
+	g.startCount()
+	hidden := make(map[deck.Rank]int)
+	if cards := g.dealer.Hand().Cards; g.rules.DealerPeeks && len(cards) > 1 && g.state != RoundOver {
+		hidden[cards[1].Rank]++
+	}
+	for _, rank := range deck.Ranks {
+		for i := hidden[rank]; i < g.deck.Dealt(rank); i++ {
+			g.counter.Observe(deck.Card{Suit: deck.Spades, Rank: rank})
+		}
+	}
+}
+
+// validateSeats checks that the saved seats can be played: at least one and no
+// more than MaxSeats, each with a uniquely named player, and the turn at one of them
+func validateSeats(seats []*Seat, turn int) error {
+	if len(seats) == 0 || len(seats) > MaxSeats {
+		return fmt.Errorf("invalid snapshot: %d seats at a table of %d", len(seats), MaxSeats)
+	}
+	if turn < 0 || (turn >= len(seats) && turn != 0) {
+		return fmt.Errorf("invalid snapshot: turn at seat %d of %d", turn, len(seats))
+	}
+
+	names := make(map[string]bool)
+	for _, seat := range seats {
+		if seat == nil || seat.Player == nil {
+			return fmt.Errorf("invalid snapshot: empty seat")
+		}
+		if names[seat.Player.Name] {
+			return fmt.Errorf("invalid snapshot: %q is seated twice", seat.Player.Name)
+		}
+		names[seat.Player.Name] = true
+		if err := validateHands(seat.Player); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateHands checks that the player has a hand at their active position
+func validateHands(p *player.Player) error {
+	if len(p.Hands) == 0 || p.Active < 0 || p.Active >= len(p.Hands) {
+		return fmt.Errorf("invalid snapshot: %s has no hand at position %d", p.Name, p.Active)
+	}
+	return nil
+}
+
+// MarshalJSON saves the complete game state
+func (g *Game) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.Snapshot())
+}
+
+// UnmarshalJSON restores a game saved by MarshalJSON
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	restored, err := FromSnapshot(s)
+	if err != nil {
+		return err
+	}
+	*g = *restored
+	return nil
+}
@@ -0,0 +1,165 @@
+package game
+
+import (
+	"blackjack/internal/count"
+	"blackjack/internal/rules"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestGameJSON tests saving a game mid-round and loading it back
+func TestGameJSON(t *testing.T) {
+	original := newTestGame(t, WithSeed(testSeed))
+	original.StartRound()
+	original.seats[0].Score = Score{Wins: 3, Losses: 2, Pushes: 1}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{`"state":"PlayerTurn"`, `"wins":3`, `"name":"Test Player"`, `"name":"Dealer"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected saved game to contain %s", want)
+		}
+	}
+
+	var restored Game
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if restored.GetState() != PlayerTurn || restored.GetScore() != original.GetScore() {
+		t.Errorf("Expected state %v and score %+v, got %v and %+v",
+			PlayerTurn, original.GetScore(), restored.GetState(), restored.GetScore())
+	}
+	if restored.String() != original.String() {
+		t.Errorf("Restored game differs:\n%s\nvs\n%s", restored.String(), original.String())
+	}
+
+	// Both games must play out identically from here
+	for _, g := range []*Game{original, &restored} {
+		g.PlayerHit()
+		if g.GetState() == PlayerTurn {
+			g.PlayerStand()
+		}
+		if g.GetState() == DealerTurn {
+			g.DealerPlay()
+		}
+	}
+	if restored.GetResult() != original.GetResult() || restored.String() != original.String() {
+		t.Error("Restored game played out differently from the original")
+	}
+	if restored.deck.RemainingCards() != original.deck.RemainingCards() {
+		t.Error("Restored shoe has a different number of cards")
+	}
+
+	// A finished round keeps its settled result
+	data, _ = json.Marshal(original)
+	var finished Game
+	if err := json.Unmarshal(data, &finished); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if finished.GetResult() == "" || finished.GetResult() != original.GetResult() {
+		t.Errorf("Expected result %q after restoring, got %q", original.GetResult(), finished.GetResult())
+	}
+
+	// Each invalid snapshot differs from the valid one in a single field
+	table, _ := json.Marshal(rules.DefaultTableRules())
+	load := func(seats, extra string) error {
+		input := fmt.Sprintf(`{"seats":%s,"dealer":{"hands":[{}]},"deck":{"decks":1,"cards":[]},"state":"RoundOver","rules":%s%s}`, seats, table, extra)
+		var g Game
+		return json.Unmarshal([]byte(input), &g)
+	}
+	seat := `{"player":{"name":"A","hands":[{}]}}`
+	if err := load("["+seat+"]", ""); err != nil {
+		t.Fatalf("Unexpected error loading a valid snapshot: %v", err)
+	}
+
+	invalid := []struct{ seats, extra string }{
+		{"[" + seat + "]", `,"state":"Sleeping"`},
+		{"[" + seat + "]", `,"deck":{"decks":5,"cards":[]}`},
+		{"[" + seat + "]", `,"dealer":null`},
+		{"[" + seat + "]", `,"turn":1`},
+		{`[{"player":{"hands":[]}}]`, ""},
+		{`[{"player":{"hands":[{}],"active":1}}]`, ""},
+		{"[" + seat + "," + seat + "]", ""},
+		{"[null]", ""},
+		{"[]", ""},
+		{"[" + strings.Repeat(`{"player":{"hands":[{}]}},`, MaxSeats) + seat + "]", ""},
+	}
+	for _, input := range invalid {
+		if err := load(input.seats, input.extra); err == nil {
+			t.Errorf("Expected error loading seats %s with %s", input.seats, input.extra)
+		}
+	}
+	var g Game
+	if err := json.Unmarshal([]byte(`{}`), &g); err == nil {
+		t.Error("Expected error loading an empty snapshot")
+	}
+}
+
+// TestFromSnapshot tests restoring a game with new options
+func TestFromSnapshot(t *testing.T) {
+	original := newTestGame(t)
+	g, err := FromSnapshot(original.Snapshot(), WithSeed(1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if g.deck != original.deck || g.seats[0] != original.seats[0] {
+		t.Error("Expected game to continue with the snapshot's shoe and hands")
+	}
+
+	// The count is rebuilt from the shoe, leaving out the face-down hole card
+	stacked := newTestGame(t, WithStackedDeck(mustParseCards(t, "9H 6S 9C TS AD")))
+	stacked.StartRound()
+	g, err = FromSnapshot(stacked.Snapshot(), WithCountingSystem(count.Zen))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := g.Counter().Running(); got != 2 || g.Counter().Seen() != 3 {
+		t.Errorf("Expected a Zen count of +2 from 3 cards, got %v", g.Counter())
+	}
+
+	// A secure shoe resumes with a commitment of its own and reveals the one it replaced
+	secure := newTestGame(t, WithSecureShuffle())
+	committed := secure.ShuffleCommitment()
+	data, err := json.Marshal(secure)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if g, err = FromSnapshot(snapshot); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if proof, ok := g.RevealShuffle(); g.ShuffleCommitment() == "" || !ok || proof.Commitment != committed || !proof.Verify() {
+		t.Error("Expected the resumed secure shoe to keep a checkable commitment")
+	}
+
+	if _, err := FromSnapshot(original.Snapshot(), WithSecureShuffle(), WithSeed(1)); err == nil {
+		t.Error("Expected error combining secure shuffling with a seed")
+	}
+}
+
+// TestGameStateText tests the names of game states
+func TestGameStateText(t *testing.T) {
+	for _, state := range []GameState{WaitingToStart, PlayerTurn, DealerTurn, RoundOver, InsuranceOffered} {
+		text, err := state.MarshalText()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var decoded GameState
+		if err := decoded.UnmarshalText(text); err != nil || decoded != state {
+			t.Errorf("State %v did not round trip: got %v (%v)", state, decoded, err)
+		}
+	}
+	if _, err := GameState(99).MarshalText(); err == nil {
+		t.Error("Expected error for invalid state")
+	}
+	if GameState(99).String() != "Unknown" {
+		t.Error("Expected unknown state name")
+	}
+}
@@ -0,0 +1,116 @@
+package odds
+
+import (
+	"blackjack/internal/deck"
+	"blackjack/internal/player"
+	"blackjack/internal/rules"
+)
+
+// dealerKey identifies a dealer hand part way through drawing
+type dealerKey struct {
+	shoe  source
+	total int
+	soft  bool
+}
+
+// Calculator works out exact odds under one set of table rules, remembering
+// every position it has solved so repeated questions are answered at once
+type Calculator struct {
+	rules  rules.TableRules
+	dealer map[dealerKey]DealerOdds
+}
+
+// NewCalculator returns a calculator for the table rules
+func NewCalculator(table rules.TableRules) *Calculator {
+	return &Calculator{rules: table, dealer: make(map[dealerKey]DealerOdds)}
+}
+
+// Dealer returns the chance of each dealer result for the upcard, with the hole
+// card and any draws coming from the unseen cards. When the table's dealer peeks
+// under an Ace or ten, the odds are given knowing there was no BlackJack.
+// If the unseen cards run out, the dealer stands on the total reached, even
+// below 17, as no shoe is reshuffled part way through a hand.
+func (c *Calculator) Dealer(upcard deck.Card, shoe Counts) DealerOdds {
+	return c.start(upcard.Value(), shoe)
+}
+
+// start returns the odds of each dealer result for the upcard from any source of cards
+func (c *Calculator) start(up int, shoe source) DealerOdds {
+	total, soft := player.AddValue(0, false, up)
+
+	// The hole card that would complete a BlackJack, if any
+	natural := 0
+	switch up {
+	case ace:
+		natural = 10
+	case 10:
+		natural = ace
+	}
+
+	var result DealerOdds
+	chances, ok := shoe.chances()
+	left := 1.0
+	if c.rules.DealerPeeks && natural != 0 {
+		left -= chances[natural] // The peek showed the hole card is not this
+	}
+	if !ok || left <= 0 {
+		result.Totals[total] = 1 // No hole card left to deal: the dealer stands on the upcard
+		return result
+	}
+
+	for hole := 2; hole <= ace; hole++ {
+		if chances[hole] == 0 {
+			continue
+		}
+		chance := chances[hole] / left
+		switch {
+		case hole == natural && c.rules.DealerPeeks:
+			continue
+		case hole == natural:
+			result.Natural += chance
+		default:
+			next, nextSoft := player.AddValue(total, soft, hole)
+			result.add(c.finish(shoe.draw(hole), next, nextSoft), chance)
+		}
+	}
+	return result
+}
+
+// finish returns the odds of each result as the dealer draws on from a total
+func (c *Calculator) finish(shoe source, total int, soft bool) DealerOdds {
+	var result DealerOdds
+	switch {
+	case total > 21:
+		result.Bust = 1
+		return result
+	case !c.rules.DealerHits(total, soft):
+		result.Totals[total] = 1
+		return result
+	}
+
+	key := dealerKey{shoe, total, soft}
+	if cached, ok := c.dealer[key]; ok {
+		return cached
+	}
+
+	chances, ok := shoe.chances()
+	if !ok {
+		result.Totals[total] = 1 // Out of cards: the dealer stands where they are
+		return result
+	}
+	for value := 2; value <= ace; value++ {
+		if chances[value] == 0 {
+			continue
+		}
+		next, nextSoft := player.AddValue(total, soft, value)
+		result.add(c.finish(shoe.draw(value), next, nextSoft), chances[value])
+	}
+	c.dealer[key] = result
+	return result
+}
+
+// Dealer returns the chance of each dealer result for the upcard from the unseen cards
+// Use a Calculator to answer many questions under the same rules
+func Dealer(upcard deck.Card, shoe Counts, table rules.TableRules) DealerOdds {
+	return NewCalculator(table).Dealer(upcard, shoe)
+}
@@ -0,0 +1,134 @@
+package odds
+
+import (
+	"blackjack/internal/deck"
+	"blackjack/internal/player"
+	"blackjack/internal/rules"
+	"blackjack/internal/strategy"
+	"math"
+	"strings"
+	"testing"
+)
+
+// sum adds up every result, which should come to one
+func sum(d DealerOdds) float64 {
+	total := d.Bust + d.Natural
+	for _, chance := range d.Totals {
+		total += chance
+	}
+	return total
+}
+
+// card returns a card of the given rank
+func card(rank deck.Rank) deck.Card {
+	return deck.Card{Suit: deck.Spades, Rank: rank}
+}
+
+// TestDealerSmallShoe tests odds worked out by hand for a three-card shoe
+func TestDealerSmallShoe(t *testing.T) {
+	// Dealer shows a six; an Ace, a ten and a six are left
+	var shoe Counts
+	shoe[ace], shoe[10], shoe[6] = 1, 1, 1
+
+	table := rules.DefaultTableRules()
+	s17 := Dealer(card(deck.Six), shoe, table)
+	if math.Abs(s17.Totals[17]-0.5) > 1e-12 || math.Abs(s17.Bust-0.5) > 1e-12 {
+		t.Errorf("S17: expected 17 and bust half the time each, got %v", s17)
+	}
+
+	// Hitting soft 17 turns one of the dealer's 17s into a bust
+	table.DealerHitsSoft17 = true
+	h17 := Dealer(card(deck.Six), shoe, table)
+	if math.Abs(h17.Totals[17]-1.0/3) > 1e-12 || math.Abs(h17.Bust-2.0/3) > 1e-12 {
+		t.Errorf("H17: expected 17 a third of the time and bust otherwise, got %v", h17)
+	}
+}
+
+// TestDealerPeek tests that a peek rules out the dealer BlackJack
+func TestDealerPeek(t *testing.T) {
+	shoe := CountDeck(mustShoe(t, 1))
+	shoe[ace]-- // The upcard
+
+	table := rules.DefaultTableRules()
+	peeked := Dealer(card(deck.Ace), shoe, table)
+	if peeked.Natural != 0 || math.Abs(sum(peeked)-1) > 1e-9 {
+		t.Errorf("Expected no BlackJack after a peek and odds adding to one, got %v", peeked)
+	}
+
+	table.DealerPeeks = false
+	open := Dealer(card(deck.Ace), shoe, table)
+	if math.Abs(open.Natural-16.0/51) > 1e-12 || math.Abs(sum(open)-1) > 1e-9 {
+		t.Errorf("Expected a 16/51 chance of BlackJack without a peek, got %v", open)
+	}
+	if math.Abs(open.Bust-peeked.Bust*(1-open.Natural)) > 1e-9 {
+		t.Errorf("Expected the peeked odds to be the open odds without BlackJacks, got %v and %v", peeked, open)
+	}
+}
+
+// TestDealerLargeShoe tests an eight-deck shoe against the well-known infinite-deck figures
+func TestDealerLargeShoe(t *testing.T) {
+	shoe := CountDeck(mustShoe(t, 8))
+	table := rules.DefaultTableRules()
+	calc := NewCalculator(table)
+
+	tests := []struct {
+		rank deck.Rank
+		bust float64
+	}{
+		{deck.Two, 0.354},
+		{deck.Six, 0.423},
+		{deck.Seven, 0.262},
+		{deck.Ten, 0.230},
+	}
+	for _, test := range tests {
+		left := shoe
+		left[card(test.rank).Value()]--
+		d := calc.Dealer(card(test.rank), left)
+		if math.Abs(d.Bust-test.bust) > 0.005 {
+			t.Errorf("Dealer showing %s: expected to bust about %.3f, got %.4f", test.rank, test.bust, d.Bust)
+		}
+		if math.Abs(sum(d)-1) > 1e-9 {
+			t.Errorf("Dealer showing %s: odds add up to %v", test.rank, sum(d))
+		}
+	}
+}
+
+// TestDealerExhaustedShoe tests that a dealer who runs out of cards stands on
+// the total reached, and that the odds still add up to one
+func TestDealerExhaustedShoe(t *testing.T) {
+	table := rules.DefaultTableRules()
+
+	// Showing a six with only a two and a three left, the dealer reaches 11 either way
+	var shoe Counts
+	shoe[2], shoe[3] = 1, 1
+	d := Dealer(card(deck.Six), shoe, table)
+	if d.Totals[11] != 1 || math.Abs(sum(d)-1) > 1e-12 {
+		t.Errorf("Expected the dealer to stand on 11, got %v", d)
+	}
+	if !strings.Contains(d.String(), "11 (out of cards): 100.0%") {
+		t.Errorf("Expected the short total to be listed, got %q", d.String())
+	}
+
+	// A player on 12 beats the dealer's 11, and a hit finds no card to draw
+	evs := ActionEV(&player.Hand{Cards: []deck.Card{card(deck.Seven), card(deck.Five)}}, card(deck.Six), shoe, table, rules.Actions{})
+	if evs[strategy.Stand] != 1 {
+		t.Errorf("Expected standing on 12 to win, got %v", evs)
+	}
+
+	// With no hole card to deal, or only the card a peek ruled out, the dealer stands on the upcard
+	tens := Counts{}
+	tens[10] = 3
+	tests := []struct {
+		name  string
+		odds  DealerOdds
+		total int
+	}{
+		{"Empty shoe", Dealer(card(deck.Six), Counts{}, table), 6},
+		{"Only BlackJack cards", Dealer(card(deck.Ace), tens, table), 11},
+	}
+	for _, test := range tests {
+		if test.odds.Totals[test.total] != 1 || math.Abs(sum(test.odds)-1) > 1e-12 {
+			t.Errorf("%s: expected the dealer to stand on %d, got %v", test.name, test.total, test.odds)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package odds
+
+import (
+	"blackjack/internal/deck"
+	"blackjack/internal/player"
+	"blackjack/internal/rules"
+	"blackjack/internal/strategy"
+)
+
+// playerKey identifies a player's hand part way through drawing
+type playerKey struct {
+	shoe  source
+	total int
+	soft  bool
+}
+
+// evaluation works out expected values against one dealer upcard
+type evaluation struct {
+	calc   *Calculator
+	upcard deck.Card
+	dealer map[source]DealerOdds
+	best   map[playerKey]float64
+}
+
+// dealerOdds returns the dealer's odds once the player has drawn down to the given cards
+func (e *evaluation) dealerOdds(shoe source) DealerOdds {
+	if cached, ok := e.dealer[shoe]; ok {
+		return cached
+	}
+	d := e.calc.start(e.upcard.Value(), shoe)
+	e.dealer[shoe] = d
+	return d
+}
+
+// stand returns the expected value of standing on a total
+func (e *evaluation) stand(total int, shoe source) float64 {
+	if total > 21 {
+		return -1
+	}
+	d := e.dealerOdds(shoe)
+	ev := d.Bust - d.Natural
+	for final, chance := range d.Totals {
+		switch {
+		case total > final:
+			ev += chance
+		case total < final:
+			ev -= chance
+		}
+	}
+	return ev
+}
+
+// draw returns the expected value of taking one card from the shoe, then
+// playing on with the given value function
+func (e *evaluation) draw(total int, soft bool, shoe source, then func(int, bool, source) float64) float64 {
+	chances, ok := shoe.chances()
+	if !ok {
+		return e.stand(total, shoe)
+	}
+
+	ev := 0.0
+	for value := 2; value <= ace; value++ {
+		if chances[value] == 0 {
+			continue
+		}
+		next, nextSoft := player.AddValue(total, soft, value)
+		if next > 21 {
+			ev -= chances[value]
+		} else {
+			ev += chances[value] * then(next, nextSoft, shoe.draw(value))
+		}
+	}
+	return ev
+}
+
+// bestPlay returns the expected value of hitting or standing, whichever is better
+func (e *evaluation) bestPlay(total int, soft bool, shoe source) float64 {
+	if total == 21 {
+		return e.stand(total, shoe)
+	}
+	key := playerKey{shoe, total, soft}
+	if cached, ok := e.best[key]; ok {
+		return cached
+	}
+	ev := max(e.stand(total, shoe), e.hit(total, soft, shoe))
+	e.best[key] = ev
+	return ev
+}
+
+// hit returns the expected value of taking a card and playing on as well as possible
+func (e *evaluation) hit(total int, soft bool, shoe source) float64 {
+	return e.draw(total, soft, shoe, e.bestPlay)
+}
+
+// double returns the expected value of doubling, as a fraction of the original bet
+func (e *evaluation) double(total int, soft bool, shoe source) float64 {
+	return 2 * e.draw(total, soft, shoe, func(total int, _ bool, shoe source) float64 {
+		return e.stand(total, shoe)
+	})
+}
+
+// split returns the expected value of splitting a pair, as a fraction of the original bet
+// Both hands are valued as the first one, played without splitting again;
+// split Aces take one card each
+func (e *evaluation) split(card int, shoe source) float64 {
+	table := e.calc.rules
+	start, soft := player.AddValue(0, false, card)
+	return 2 * e.draw(start, soft, shoe, func(total int, soft bool, shoe source) float64 {
+		if card == ace {
+			return e.stand(total, shoe)
+		}
+		ev := e.bestPlay(total, soft, shoe)
+		if table.DoubleAfterSplit && table.Double.Allows(total) {
+			ev = max(ev, e.double(total, soft, shoe))
+		}
+		return ev
+	})
+}
+
+// ActionEV returns the expected value of each legal action for the player's
+// cards against the dealer's upcard, as a fraction of the hand's bet. Every
+// card the player or dealer might draw comes from the unseen cards, and later
+// decisions are played as well as possible given the cards drawn, so the
+// result depends on the exact composition of the shoe rather than a chart.
+func (c *Calculator) ActionEV(cards []deck.Card, upcard deck.Card, shoe Counts, legal rules.Actions) map[strategy.Action]float64 {
+	return c.actionEV(cards, upcard, shoe, legal)
+}
+
+// InfiniteActionEV returns the expected value of each legal action for the
+// player's cards against the dealer's upcard, as a fraction of the hand's bet,
+// drawing from an infinite shoe as basic strategy assumes. The cards already
+// dealt never change the odds, so the result suits every shoe alike.
+func (c *Calculator) InfiniteActionEV(cards []deck.Card, upcard deck.Card, legal rules.Actions) map[strategy.Action]float64 {
+	return c.actionEV(cards, upcard, infinite{}, legal)
+}
+
+// actionEV returns the expected value of each legal action with the cards drawn from the given source
+func (c *Calculator) actionEV(cards []deck.Card, upcard deck.Card, shoe source, legal rules.Actions) map[strategy.Action]float64 {
+	value := player.Evaluate(cards)
+	if value.Busted {
+		return map[strategy.Action]float64{}
+	}
+
+	e := &evaluation{calc: c, upcard: upcard, dealer: make(map[source]DealerOdds), best: make(map[playerKey]float64)}
+	evs := map[strategy.Action]float64{
+		strategy.Hit:   e.hit(value.Total, value.Soft, shoe),
+		strategy.Stand: e.stand(value.Total, shoe),
+	}
+	if legal.Double {
+		evs[strategy.Double] = e.double(value.Total, value.Soft, shoe)
+	}
+	if legal.Split && value.Pair {
+		evs[strategy.Split] = e.split(cards[0].Value(), shoe)
+	}
+	if !legal.Surrender {
+		return evs
+	}
+
+	// The chance the unseen hole card completes a dealer BlackJack
+	natural := 0.0
+	if chances, ok := shoe.chances(); ok {
+		switch upcard.Value() {
+		case ace:
+			natural = chances[10]
+		case 10:
+			natural = chances[ace]
+		}
+	}
+
+	evs[strategy.Surrender] = -0.5
+	switch {
+	case !c.rules.DealerPeeks && c.rules.Surrender == rules.LateSurrender:
+		// Without a peek, a late surrender still loses the whole bet to a BlackJack
+		evs[strategy.Surrender] = -0.5*(1-natural) - natural
+	case c.rules.DealerPeeks && c.rules.Surrender == rules.EarlySurrender:
+		// Early surrender comes before the dealer checks, when a BlackJack
+		// would still take the original bet from every other play
+		for action, ev := range evs {
+			if action != strategy.Surrender {
+				evs[action] = ev*(1-natural) - natural
+			}
+		}
+	}
+	return evs
+}
+
+// ActionEV returns the expected value of each legal action for a hand from the unseen cards
+// Use a Calculator to answer many questions under the same rules
+func ActionEV(hand *player.Hand, upcard deck.Card, shoe Counts, table rules.TableRules, legal rules.Actions) map[strategy.Action]float64 {
+	return NewCalculator(table).ActionEV(hand.Cards, upcard, shoe, legal)
+}
+
+// InfiniteActionEV returns the expected value of each legal action for a hand
+// from an infinite shoe, as basic strategy assumes
+func InfiniteActionEV(hand *player.Hand, upcard deck.Card, table rules.TableRules, legal rules.Actions) map[strategy.Action]float64 {
+	return NewCalculator(table).InfiniteActionEV(hand.Cards, upcard, legal)
+}
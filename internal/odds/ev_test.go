@@ -0,0 +1,219 @@
+package odds
+
+import (
+	"blackjack/internal/deck"
+	"blackjack/internal/player"
+	"blackjack/internal/rules"
+	"blackjack/internal/strategy"
+	"math"
+	"testing"
+)
+
+// allLegal allows every optional action
+var allLegal = rules.Actions{Double: true, Split: true, Surrender: true}
+
+// TestActionEVTensOnly tests values worked out by hand for a shoe of nothing but tens
+func TestActionEVTensOnly(t *testing.T) {
+	var shoe Counts
+	shoe[10] = 20
+	calc := NewCalculator(rules.DefaultTableRules())
+
+	// The dealer will turn a seven into 17; any ten busts a 12 and makes 11 into 21
+	tests := []struct {
+		name     string
+		cards    []deck.Card
+		expected map[strategy.Action]float64
+	}{
+		{"Hard 12", []deck.Card{card(deck.Seven), card(deck.Five)}, map[strategy.Action]float64{
+			strategy.Stand: -1, strategy.Hit: -1, strategy.Double: -2,
+		}},
+		{"Hard 11", []deck.Card{card(deck.Six), card(deck.Five)}, map[strategy.Action]float64{
+			strategy.Stand: -1, strategy.Hit: 1, strategy.Double: 2,
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			evs := calc.ActionEV(test.cards, card(deck.Seven), shoe, rules.Actions{Double: true})
+			if len(evs) != len(test.expected) {
+				t.Errorf("Expected %d actions, got %v", len(test.expected), evs)
+			}
+			for action, expected := range test.expected {
+				if math.Abs(evs[action]-expected) > 1e-12 {
+					t.Errorf("%s: expected %.3f, got %.3f", action, expected, evs[action])
+				}
+			}
+		})
+	}
+}
+
+// TestActionEVLargeShoe tests that a very large shoe agrees with the infinite-shoe figures
+func TestActionEVLargeShoe(t *testing.T) {
+	var shoe Counts
+	for value := 2; value <= ace; value++ {
+		shoe[value] = 4 * 64
+	}
+	shoe[10] = 16 * 64
+	table := rules.DefaultTableRules()
+	calc := NewCalculator(table)
+
+	tests := []struct {
+		name   string
+		cards  []deck.Card
+		upcard deck.Card
+	}{
+		{"16 against a ten", []deck.Card{card(deck.Ten), card(deck.Six)}, card(deck.King)},
+		{"11 against a six", []deck.Card{card(deck.Six), card(deck.Five)}, card(deck.Six)},
+		{"Soft 18 against a nine", []deck.Card{card(deck.Ace), card(deck.Seven)}, card(deck.Nine)},
+		{"Eights against an Ace", []deck.Card{card(deck.Eight), card(deck.Eight)}, card(deck.Ace)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exact := calc.ActionEV(test.cards, test.upcard, shoe, allLegal)
+			infinite := calc.InfiniteActionEV(test.cards, test.upcard, allLegal)
+			for action, ev := range infinite {
+				if math.Abs(exact[action]-ev) > 0.01 {
+					t.Errorf("%s: expected about %.3f, got %.3f", action, ev, exact[action])
+				}
+			}
+		})
+	}
+}
+
+// TestActionEVBusted tests that a busted hand has nothing left to decide
+func TestActionEVBusted(t *testing.T) {
+	hand := &player.Hand{Cards: []deck.Card{card(deck.Ten), card(deck.Nine), card(deck.Five)}}
+	shoe := CountDeck(mustShoe(t, 1))
+	if evs := ActionEV(hand, card(deck.Six), shoe, rules.DefaultTableRules(), allLegal); len(evs) != 0 {
+		t.Errorf("Expected no actions for a busted hand, got %v", evs)
+	}
+}
+
+// TestChartAgainstOracle checks the basic strategy chart against the exact
+// values for every two-card starting hand from a fresh single deck. Basic
+// strategy ignores the exact cards, so it may give up a little, but never much.
+func TestChartAgainstOracle(t *testing.T) {
+	table := rules.DefaultTableRules()
+	table.Decks = 1
+	chart := strategy.NewChart(table)
+	calc := NewCalculator(table)
+	full := CountDeck(mustShoe(t, 1))
+
+	ranks := []deck.Rank{deck.Two, deck.Three, deck.Four, deck.Five, deck.Six, deck.Seven, deck.Eight, deck.Nine, deck.Ten, deck.Ace}
+	for _, up := range ranks {
+		for i, first := range ranks {
+			for _, second := range ranks[i:] {
+				cards := []deck.Card{card(first), card(second)}
+				if player.Evaluate(cards).Natural {
+					continue
+				}
+				shoe := full
+				for _, c := range append(cards, card(up)) {
+					shoe = shoe.without(c.Value())
+				}
+
+				legal := rules.Actions{Double: true, Split: first == second, Surrender: table.Surrender != rules.NoSurrender}
+				evs := calc.ActionEV(cards, card(up), shoe, legal)
+				chosen := chart.Action(cards, card(up), legal)
+				for action, ev := range evs {
+					if ev > evs[chosen]+0.03 {
+						t.Errorf("%s,%s against %s: chart plays %s (%.3f) but %s gives %.3f",
+							first, second, up, chosen, evs[chosen], action, ev)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestInfiniteActionEV tests expected values against well-known infinite-shoe results
+func TestInfiniteActionEV(t *testing.T) {
+	table := rules.DefaultTableRules()
+	table.Decks = 6
+	calc := NewCalculator(table)
+
+	tests := []struct {
+		name     string
+		cards    string
+		upcard   string
+		action   strategy.Action
+		expected float64
+	}{
+		{"Double 11 against a six", "6H 5S", "6D", strategy.Double, 0.667},
+		{"Stand on 16 against a ten", "TH 6S", "KD", strategy.Stand, -0.540},
+		{"Hit 16 against a ten", "TH 6S", "KD", strategy.Hit, -0.540},
+		{"Stand on 20 against a ten", "KH QS", "TD", strategy.Stand, 0.554},
+		{"Stand on 12 against a six", "TH 2S", "6D", strategy.Stand, -0.154},
+		{"Surrender", "TH 6S", "KD", strategy.Surrender, -0.5},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			evs := calc.InfiniteActionEV(mustCards(t, test.cards), mustCards(t, test.upcard)[0], allLegal)
+			if got := evs[test.action]; math.Abs(got-test.expected) > 0.005 {
+				t.Errorf("Expected EV of %s near %.3f, got %.4f", test.action, test.expected, got)
+			}
+		})
+	}
+
+	t.Run("Only legal actions", func(t *testing.T) {
+		evs := calc.InfiniteActionEV(mustCards(t, "6H 5S"), mustCards(t, "6D")[0], rules.Actions{})
+		if len(evs) != 2 {
+			t.Errorf("Expected only hit and stand, got %v", evs)
+		}
+	})
+	t.Run("Busted hand", func(t *testing.T) {
+		if evs := calc.InfiniteActionEV(mustCards(t, "KH QS 5D"), mustCards(t, "6D")[0], allLegal); len(evs) != 0 {
+			t.Errorf("Expected no actions, got %v", evs)
+		}
+	})
+	t.Run("No hole card", func(t *testing.T) {
+		noPeek := table
+		noPeek.DealerPeeks = false
+		noPeek.Surrender = rules.LateSurrender
+		peek := calc.InfiniteActionEV(mustCards(t, "6H 5S"), mustCards(t, "AD")[0], allLegal)
+		enhc := NewCalculator(noPeek).InfiniteActionEV(mustCards(t, "6H 5S"), mustCards(t, "AD")[0], allLegal)
+		if enhc[strategy.Double] >= peek[strategy.Double] || enhc[strategy.Surrender] >= -0.5 {
+			t.Errorf("Expected a dealer BlackJack to cost more without a peek, got %v and %v", peek, enhc)
+		}
+	})
+	t.Run("Early surrender", func(t *testing.T) {
+		early := table
+		early.Surrender = rules.EarlySurrender
+		late := calc.InfiniteActionEV(mustCards(t, "TH 6S"), mustCards(t, "AD")[0], allLegal)
+		evs := NewCalculator(early).InfiniteActionEV(mustCards(t, "TH 6S"), mustCards(t, "AD")[0], allLegal)
+		if evs[strategy.Surrender] != -0.5 || evs[strategy.Stand] >= late[strategy.Stand] {
+			t.Errorf("Expected plays other than surrender to risk the dealer BlackJack, got %v", evs)
+		}
+	})
+}
+
+// TestChartAgreesWithInfiniteEV tests that the generated chart never gives up
+// much against the best play from an infinite shoe
+func TestChartAgreesWithInfiniteEV(t *testing.T) {
+	table := rules.DefaultTableRules()
+	table.Decks = 6
+	chart := strategy.NewChart(table)
+	calc := NewCalculator(table)
+	all := rules.Actions{Double: true, Split: true}
+
+	for _, first := range deck.Ranks[:10] {
+		for _, second := range deck.Ranks[:10] {
+			for _, up := range deck.Ranks[:10] {
+				cards := []deck.Card{{Suit: deck.Hearts, Rank: first}, {Suit: deck.Spades, Rank: second}}
+				upcard := deck.Card{Suit: deck.Clubs, Rank: up}
+				if cards[0].Value()+cards[1].Value() == 21 {
+					continue // A BlackJack needs no decision
+				}
+
+				evs := calc.InfiniteActionEV(cards, upcard, all)
+				best := math.Inf(-1)
+				for _, ev := range evs {
+					best = max(best, ev)
+				}
+				action := chart.Action(cards, upcard, all)
+				if loss := best - evs[action]; loss > 0.02 {
+					t.Errorf("%s against %s: chart says %s, giving up %.3f", deck.FormatCards(cards), upcard.Code(), action, loss)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package odds works out exact BlackJack probabilities from the cards left in the shoe
+package odds
+
+import (
+	"blackjack/internal/deck"
+	"fmt"
+	"strings"
+)
+
+// ace is the index of Aces in Counts, matching their card value
+const ace = 11
+
+// Counts holds how many cards of each value are unseen, indexed by card value
+// from 2 to 11 (the Ace). Tens, Jacks, Queens and Kings all count as 10.
+type Counts [12]int
+
+// CountDeck counts the cards left in a deck
+func CountDeck(d *deck.Deck) Counts {
+	var counts Counts
+	for _, rank := range deck.Ranks {
+		counts[deck.Card{Rank: rank}.Value()] += d.Remaining(rank)
+	}
+	return counts
+}
+
+// Add counts one more unseen card, such as a dealer hole card still face down
+func (c *Counts) Add(card deck.Card) {
+	c[card.Value()]++
+}
+
+// Total returns the number of unseen cards
+func (c Counts) Total() int {
+	total := 0
+	for value := 2; value <= ace; value++ {
+		total += c[value]
+	}
+	return total
+}
+
+// without returns the counts after one card of the given value is drawn
+func (c Counts) without(value int) Counts {
+	c[value]--
+	return c
+}
+
+// source is where the cards drawn come from: the exact unseen Counts, or an
+// infinite shoe whose odds never change
+type source interface {
+	// chances returns the chance of drawing each card value, indexed like
+	// Counts, and reports whether any cards are left to draw
+	chances() ([12]float64, bool)
+
+	// draw returns the shoe left after a card of the given value is drawn
+	draw(value int) source
+}
+
+// chances returns the chance of drawing each card value from the unseen cards
+func (c Counts) chances() ([12]float64, bool) {
+	var chances [12]float64
+	left := c.Total()
+	if left == 0 {
+		return chances, false
+	}
+	for value := 2; value <= ace; value++ {
+		chances[value] = float64(c[value]) / float64(left)
+	}
+	return chances, true
+}
+
+// draw returns the unseen cards after one of the given value is drawn
+func (c Counts) draw(value int) source {
+	return c.without(value)
+}
+
+// infinite is a shoe so large that drawing never changes the odds, as basic
+// strategy assumes: each value has one chance in 13, and tens four
+type infinite struct{}
+
+// infiniteChances holds the fixed chance of drawing each card value
+var infiniteChances = func() [12]float64 {
+	var chances [12]float64
+	for value := 2; value <= ace; value++ {
+		chances[value] = 1.0 / 13
+	}
+	chances[10] = 4.0 / 13 // Tens, Jacks, Queens and Kings
+	return chances
+}()
+
+// chances returns the fixed chance of drawing each card value
+func (infinite) chances() ([12]float64, bool) {
+	return infiniteChances, true
+}
+
+// draw returns the same infinite shoe, as one card never changes it
+func (infinite) draw(int) source {
+	return infinite{}
+}
+
+// DealerOdds is the chance of each way the dealer's hand can finish
+type DealerOdds struct {
+	Totals  [22]float64 // Chance of finishing on each total: 17 to 21, or lower if the shoe runs out
+	Bust    float64
+	Natural float64 // Chance of a BlackJack; zero once the dealer has peeked
+}
+
+// add accumulates another set of odds, weighted by the chance of reaching them
+func (d *DealerOdds) add(other DealerOdds, weight float64) {
+	for total := range d.Totals {
+		d.Totals[total] += other.Totals[total] * weight
+	}
+	d.Bust += other.Bust * weight
+	d.Natural += other.Natural * weight
+}
+
+// String lists the chance of each result (e.g., "17: 16.5%, ..., Bust: 42.3%")
+// Totals below 17, reached only when the shoe runs out, are listed first
+func (d DealerOdds) String() string {
+	var parts []string
+	for total := 2; total < 17; total++ {
+		if d.Totals[total] > 0 {
+			parts = append(parts, fmt.Sprintf("%d (out of cards): %.1f%%", total, 100*d.Totals[total]))
+		}
+	}
+	for total := 17; total <= 21; total++ {
+		parts = append(parts, fmt.Sprintf("%d: %.1f%%", total, 100*d.Totals[total]))
+	}
+	if d.Natural > 0 {
+		parts = append(parts, fmt.Sprintf("BlackJack: %.1f%%", 100*d.Natural))
+	}
+	parts = append(parts, fmt.Sprintf("Bust: %.1f%%", 100*d.Bust))
+	return strings.Join(parts, ", ")
+}
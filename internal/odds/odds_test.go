@@ -0,0 +1,60 @@
+package odds
+
+import (
+	"blackjack/internal/deck"
+	"strings"
+	"testing"
+)
+
+// TestCountDeck tests counting the cards left in a deck by value
+func TestCountDeck(t *testing.T) {
+	shoe, _ := deck.NewShoe(2)
+	counts := CountDeck(shoe)
+	if counts[10] != 32 || counts[ace] != 8 || counts[2] != 8 || counts.Total() != 104 {
+		t.Errorf("Unexpected counts for a full two-deck shoe: %v", counts)
+	}
+
+	card, _ := shoe.DrawCard()
+	counts = CountDeck(shoe)
+	if counts.Total() != 103 {
+		t.Errorf("Expected 103 cards after a draw, got %d", counts.Total())
+	}
+	counts.Add(card)
+	if counts != CountDeck(mustShoe(t, 2)) {
+		t.Error("Expected adding the drawn card back to restore the full counts")
+	}
+}
+
+// TestDealerOddsString tests describing the dealer's chances
+func TestDealerOddsString(t *testing.T) {
+	var d DealerOdds
+	d.Totals[17] = 0.5
+	d.Bust = 0.5
+	if got := d.String(); got != "17: 50.0%, 18: 0.0%, 19: 0.0%, 20: 0.0%, 21: 0.0%, Bust: 50.0%" {
+		t.Errorf("Unexpected text %q", got)
+	}
+	d.Natural = 0.25
+	if !strings.Contains(d.String(), "BlackJack: 25.0%") {
+		t.Errorf("Expected the BlackJack chance, got %q", d.String())
+	}
+}
+
+// mustShoe returns a fresh, unshuffled shoe for testing
+func mustShoe(t *testing.T, decks int) *deck.Deck {
+	t.Helper()
+	shoe, err := deck.NewShoe(decks)
+	if err != nil {
+		t.Fatalf("Failed to create shoe: %v", err)
+	}
+	return shoe
+}
+
+// mustCards parses a list of cards for a test
+func mustCards(t *testing.T, s string) []deck.Card {
+	t.Helper()
+	cards, err := deck.ParseCards(s)
+	if err != nil {
+		t.Fatalf("Bad test cards %q: %v", s, err)
+	}
+	return cards
+}
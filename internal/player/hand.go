@@ -0,0 +1,133 @@
+package player
+
+import (
+	"blackjack/internal/deck"
+	"strconv"
+)
+
+// HandValue describes a hand the way the rules of BlackJack see it
+type HandValue struct {
+	Total   int  // Best total, with one Ace counted as 11 if that doesn't bust
+	Soft    bool // An Ace is being counted as 11, so one more card cannot bust the hand
+	Pair    bool // Exactly two cards of the same value, which may be split
+	Natural bool // A BlackJack: an Ace and a 10-value card as the only two cards
+	Busted  bool // The total is over 21
+}
+
+// Evaluate works out the value of a set of cards
+func Evaluate(cards []deck.Card) HandValue {
+	total := 0
+	aceCount := 0
+
+	// First pass: count all cards, treating Aces as 11
+	for _, card := range cards {
+		if card.IsAce() {
+			aceCount++
+		}
+		total += card.Value()
+	}
+
+	// Second pass: convert Aces from 11 to 1 if we're over 21
+	for aceCount > 0 && total > 21 {
+		total -= 10 // Convert one Ace from 11 to 1
+		aceCount--
+	}
+
+	return HandValue{
+		Total:   total,
+		Soft:    aceCount > 0, // Any Ace still counted as 11 makes the hand soft
+		Pair:    len(cards) == 2 && cards[0].Value() == cards[1].Value(),
+		Natural: len(cards) == 2 && total == 21,
+		Busted:  total > 21,
+	}
+}
+
+// AddValue returns the total after a card of the given value (the Ace as 11)
+// is added to a hand's total, and whether an Ace is still counted as 11.
+// It gives the same result as Evaluate without needing the cards themselves.
+func AddValue(total int, soft bool, value int) (int, bool) {
+	total += value
+	if value == 11 {
+		if soft {
+			total -= 10 // Only one Ace can count as 11
+		}
+		soft = true
+	}
+	if total > 21 && soft {
+		total -= 10
+		soft = false
+	}
+	return total, soft
+}
+
+// String returns the total, marking soft hands (e.g., "17" or "soft 17")
+func (v HandValue) String() string {
+	if v.Soft && !v.Natural {
+		return "soft " + strconv.Itoa(v.Total)
+	}
+	return strconv.Itoa(v.Total)
+}
+
+// Hand is one of a player's hands, with its own cards, wager and state
+// A player holds several hands after splitting pairs
+type Hand struct {
+	Cards   []deck.Card `json:"cards"`             // Cards in the hand
+	State   PlayerState `json:"state"`             // Current state of the hand
+	Bet     int         `json:"bet,omitempty"`     // Chips wagered on this hand
+	Doubled bool        `json:"doubled,omitempty"` // The bet was doubled for one final card
+	Split   bool        `json:"split,omitempty"`   // The hand came from a split, so 21 is not a BlackJack
+}
+
+// NewHand creates an empty hand ready to be played
+func NewHand() *Hand {
+	return &Hand{
+		Cards: make([]deck.Card, 0),
+		State: Playing,
+	}
+}
+
+// AddCard adds a card to the hand and updates its state
+func (h *Hand) AddCard(card deck.Card) {
+	h.Cards = append(h.Cards, card)
+
+	// After adding a card, check if the hand has busted
+	value := h.Evaluate()
+	if value.Busted {
+		h.State = Busted
+	} else if value.Natural {
+		h.State = BlackJack
+	}
+}
+
+// Evaluate returns the value of the hand
+// A two-card 21 on a split hand is an ordinary 21, not a natural
+func (h *Hand) Evaluate() HandValue {
+	value := Evaluate(h.Cards)
+	if h.Split {
+		value.Natural = false
+	}
+	return value
+}
+
+// Stand changes the hand's state to Standing
+func (h *Hand) Stand() {
+	h.State = Standing
+}
+
+// SplitAces reports whether the hand was made by splitting Aces,
+// which only ever receive one more card
+func (h *Hand) SplitAces() bool {
+	return h.Split && len(h.Cards) > 0 && h.Cards[0].IsAce()
+}
+
+// String returns the cards in the hand separated by commas
+func (h *Hand) String() string {
+	handStr := ""
+	for i, card := range h.Cards {
+		if i > 0 {
+			handStr += ", "
+		}
+		handStr += card.String()
+	}
+	return handStr
+}
@@ -0,0 +1,97 @@
+package player
+
+import (
+	"testing"
+
+	"blackjack/internal/deck"
+)
+
+// TestEvaluate tests the soft, pair, natural and busted flags
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		cards    string
+		expected HandValue
+		display  string
+	}{
+		{"Empty hand", "", HandValue{}, "0"},
+		{"Hard 17", "TH 7S", HandValue{Total: 17}, "17"},
+		{"Soft 17", "AH 6S", HandValue{Total: 17, Soft: true}, "soft 17"},
+		{"Soft hand turns hard", "AH 6S TD", HandValue{Total: 17}, "17"},
+		{"Two aces", "AH AS", HandValue{Total: 12, Soft: true, Pair: true}, "soft 12"},
+		{"Pair of eights", "8H 8S", HandValue{Total: 16, Pair: true}, "16"},
+		{"Mixed tens are a pair", "KH QS", HandValue{Total: 20, Pair: true}, "20"},
+		{"Natural", "AS JD", HandValue{Total: 21, Soft: true, Natural: true}, "21"},
+		{"Three-card 21 is not a natural", "7H 7S 7D", HandValue{Total: 21}, "21"},
+		{"Soft three-card 21", "AH 5S 5D", HandValue{Total: 21, Soft: true}, "soft 21"},
+		{"Busted", "KH QS 2D", HandValue{Total: 22, Busted: true}, "22"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cards, err := deck.ParseCards(test.cards)
+			if err != nil {
+				t.Fatalf("Failed to parse cards: %v", err)
+			}
+
+			got := Evaluate(cards)
+			if got != test.expected {
+				t.Errorf("Expected %+v, got %+v", test.expected, got)
+			}
+			if got.String() != test.display {
+				t.Errorf("Expected display %q, got %q", test.display, got.String())
+			}
+		})
+	}
+}
+
+// TestAddValue tests that adding cards one at a time agrees with Evaluate
+func TestAddValue(t *testing.T) {
+	for _, hand := range []string{"TH 7S", "AH 6S", "AH 6S TD", "AH AS", "AH AS AD 8C", "AS JD", "AH 5S 5D", "KH QS 2D", "5H AS AD TC"} {
+		cards, err := deck.ParseCards(hand)
+		if err != nil {
+			t.Fatalf("Failed to parse cards: %v", err)
+		}
+
+		total, soft := 0, false
+		for _, card := range cards {
+			total, soft = AddValue(total, soft, card.Value())
+		}
+		if value := Evaluate(cards); total != value.Total || soft != value.Soft {
+			t.Errorf("%s: expected %d (soft %v), got %d (soft %v)", hand, value.Total, value.Soft, total, soft)
+		}
+	}
+}
+
+// TestPlayerEvaluate tests that the player's hand is evaluated as it grows
+func TestPlayerEvaluate(t *testing.T) {
+	player := NewPlayer("Test")
+	player.AddCard(mustCreateCard(t, deck.Hearts, deck.Ace))
+	player.AddCard(mustCreateCard(t, deck.Spades, deck.Six))
+
+	if value := player.Evaluate(); !value.Soft || value.Total != 17 {
+		t.Errorf("Expected soft 17, got %+v", value)
+	}
+	player.AddCard(mustCreateCard(t, deck.Diamonds, deck.Nine))
+	if value := player.Evaluate(); value.Soft || value.Total != 16 {
+		t.Errorf("Expected hard 16, got %+v", value)
+	}
+}
+
+// TestSplitHandNotNatural tests that 21 on a split hand is not a BlackJack
+func TestSplitHandNotNatural(t *testing.T) {
+	hand := NewHand()
+	hand.Split = true
+	hand.AddCard(mustCreateCard(t, deck.Hearts, deck.Ace))
+	hand.AddCard(mustCreateCard(t, deck.Spades, deck.King))
+
+	if value := hand.Evaluate(); value.Natural || value.Total != 21 {
+		t.Errorf("Expected an ordinary 21, got %+v", value)
+	}
+	if hand.State != Playing {
+		t.Errorf("Expected state Playing, got %v", hand.State)
+	}
+	if !hand.SplitAces() {
+		t.Error("Expected hand to count as split Aces")
+	}
+}
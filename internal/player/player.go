@@ -12,92 +12,125 @@ type PlayerState int
 const (
 	// Different states a player can be in during the game
 	//iota is a special keyword that automatically assigns sequential integer values to constants starting from 0
-	Playing   PlayerState = iota // Player is still making decisions
-	Standing                     // Player has chosen to stand
-	Busted                       // Player's hand value exceeded 21
-	BlackJack                    // Player has a BlackJack (21 with 2 cards)
+	Playing     PlayerState = iota // Player is still making decisions
+	Standing                       // Player has chosen to stand
+	Busted                         // Player's hand value exceeded 21
+	BlackJack                      // Player has a BlackJack (21 with 2 cards)
+	Surrendered                    // Player gave up the hand for half the bet
 )
 
 // Player represents a player in the game
 type Player struct {
-	Name  string      // Player's name
-	Hand  []deck.Card // Cards in player's hand
-	State PlayerState // Current state of the player
+	Name     string  `json:"name"`               // Player's name
+	Hands    []*Hand `json:"hands"`              // Hands being played, more than one after a split
+	Active   int     `json:"active,omitempty"`   // Index of the hand being played
+	Bankroll int     `json:"bankroll,omitempty"` // Chips the player has left, not counting bets on the table
+	Bet      int     `json:"bet,omitempty"`      // Chips wagered on the next round, moved to the first hand when it is dealt
+
+	Insurance int  `json:"insurance,omitempty"` // Chips on the insurance side bet against a dealer BlackJack
+	EvenMoney bool `json:"evenMoney,omitempty"` // Took even money on a BlackJack against a dealer Ace
 }
 
 // NewPlayer creates a new player with the given name
 func NewPlayer(name string) *Player {
 	return &Player{
 		Name:  name,
-		Hand:  make([]deck.Card, 0), // Initialize empty hand
-		State: Playing,              // Start in Playing state
+		Hands: []*Hand{NewHand()}, // Start with one empty hand
 	}
 }
 
-// AddCard adds a card to the player's hand
+// Hand returns the hand being played
+func (p *Player) Hand() *Hand {
+	return p.Hands[p.Active]
+}
+
+// AddCard adds a card to the hand being played
 func (p *Player) AddCard(card deck.Card) {
-	p.Hand = append(p.Hand, card)
+	p.Hand().AddCard(card)
+}
 
-	// After adding a card, check if player has busted
-	if p.GetHandValue() > 21 {
-		p.State = Busted
-	} else if len(p.Hand) == 2 && p.GetHandValue() == 21 {
-		p.State = BlackJack
-	}
+// Evaluate returns the full value of the hand being played: total, soft, pair, natural and busted
+func (p *Player) Evaluate() HandValue {
+	return p.Hand().Evaluate()
 }
 
-// GetHandValue calculates the total value of the player's hand
+// GetHandValue calculates the total value of the hand being played
 func (p *Player) GetHandValue() int {
-	total := 0
-	aceCount := 0
-
-	// First pass: count all cards, treating Aces as 11
-	for _, card := range p.Hand {
-		if card.IsAce() {
-			aceCount++
-			total += 11
-		} else {
-			total += card.Value()
-		}
+	return p.Evaluate().Total
+}
+
+// Stand changes the state of the hand being played to Standing
+func (p *Player) Stand() {
+	p.Hand().Stand()
+}
+
+// Split splits the pair in the hand being played into two hands, each
+// holding one of the cards and the same bet. The new hand is played next.
+func (p *Player) Split() (*Hand, error) {
+	hand := p.Hand()
+	if !hand.Evaluate().Pair {
+		return nil, fmt.Errorf("only a pair can be split")
 	}
 
-	// Second pass: convert Aces from 11 to 1 if we're over 21
-	for aceCount > 0 && total > 21 {
-		total -= 10 // Convert one Ace from 11 to 1
-		aceCount--
+	second := &Hand{
+		Cards: []deck.Card{hand.Cards[1]},
+		State: Playing,
+		Bet:   hand.Bet,
+		Split: true,
 	}
+	hand.Cards = hand.Cards[:1]
+	hand.State = Playing
+	hand.Split = true
+
+	// Insert the new hand straight after the one being played
+	p.Hands = append(p.Hands, nil)
+	copy(p.Hands[p.Active+2:], p.Hands[p.Active+1:])
+	p.Hands[p.Active+1] = second
+	return second, nil
+}
 
-	return total
+// Splits returns the number of times the player has split this round
+func (p *Player) Splits() int {
+	return len(p.Hands) - 1
 }
 
-// Stand changes the player's state to Standing
-func (p *Player) Stand() {
-	p.State = Standing
+// NextHand moves on to the next hand, returning false if every hand has been played
+func (p *Player) NextHand() bool {
+	if p.Active+1 >= len(p.Hands) {
+		return false
+	}
+	p.Active++
+	return true
 }
 
-// ClearHand removes all cards from the player's hand
+// ClearHand removes all cards, leaving a single empty hand
 func (p *Player) ClearHand() {
-	p.Hand = p.Hand[:0] // Clear slice while preserving capacity
-	p.State = Playing   // Reset state to Playing
+	p.Hands = []*Hand{NewHand()}
+	p.Active = 0
 }
 
-// HasBlackjack checks if the player has a natural blackjack (21 with 2 cards)
+// HasBlackjack checks if the hand being played is a natural blackjack (21 with 2 cards)
 func (p *Player) HasBlackjack() bool {
-	return len(p.Hand) == 2 && p.GetHandValue() == 21
+	return p.Evaluate().Natural
 }
 
 // String returns a string representation of the player's current state
 func (p *Player) String() string {
-	handStr := ""
-	for i, card := range p.Hand {
-		if i > 0 {
-			handStr += ", "
-		}
-		handStr += card.String()
+	if len(p.Hands) == 1 {
+		return fmt.Sprintf("Player: %s\nHand: %s\nValue: %v\nState: %v",
+			p.Name, p.Hand(), p.Evaluate(), p.Hand().State)
 	}
 
-	return fmt.Sprintf("Player: %s\nHand: %s\nValue: %d\nState: %v",
-		p.Name, handStr, p.GetHandValue(), p.State)
+	result := fmt.Sprintf("Player: %s", p.Name)
+	for i, hand := range p.Hands {
+		marker := ""
+		if i == p.Active && hand.State == Playing {
+			marker = " (playing)"
+		}
+		result += fmt.Sprintf("\nHand %d%s: %s\nValue: %v\nState: %v",
+			i+1, marker, hand, hand.Evaluate(), hand.State)
+	}
+	return result
 }
 
 // String returns a string representation of the PlayerState
@@ -111,7 +144,28 @@ func (s PlayerState) String() string {
 		return "Busted"
 	case BlackJack:
 		return "BlackJack"
+	case Surrendered:
+		return "Surrendered"
 	default:
-		return "Unknown" // Default case if the state is not one of the above	
+		return "Unknown" // Default case if the state is not one of the above
+	}
+}
+
+// MarshalText encodes the state by name (e.g., "Standing") so saved games stay readable
+func (s PlayerState) MarshalText() ([]byte, error) {
+	if s < Playing || s > Surrendered {
+		return nil, fmt.Errorf("invalid player state: %d", int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a state name written by MarshalText
+func (s *PlayerState) UnmarshalText(text []byte) error {
+	for state := Playing; state <= Surrendered; state++ {
+		if state.String() == string(text) {
+			*s = state
+			return nil
+		}
 	}
+	return fmt.Errorf("invalid player state: %q", text)
 }
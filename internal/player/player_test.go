@@ -1,6 +1,7 @@
 package player
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -15,10 +16,10 @@ func TestNewPlayer(t *testing.T) {
 	if player.Name != name {
 		t.Errorf("Expected player name %s, got %s", name, player.Name)
 	}
-	if player.State != Playing {
-		t.Errorf("Expected initial state Playing, got %v", player.State)
+	if player.Hand().State != Playing {
+		t.Errorf("Expected initial state Playing, got %v", player.Hand().State)
 	}
-	if len(player.Hand) != 0 {
+	if len(player.Hand().Cards) != 0 {
 		t.Error("Expected empty hand")
 	}
 }
@@ -33,17 +34,17 @@ func TestAddCard(t *testing.T) {
 
 	// Add first card
 	player.AddCard(card1)
-	if len(player.Hand) != 1 {
-		t.Errorf("Expected hand size 1, got %d", len(player.Hand))
+	if len(player.Hand().Cards) != 1 {
+		t.Errorf("Expected hand size 1, got %d", len(player.Hand().Cards))
 	}
 
 	// Add second card (should be blackjack)
 	player.AddCard(card2)
-	if len(player.Hand) != 2 {
-		t.Errorf("Expected hand size 2, got %d", len(player.Hand))
+	if len(player.Hand().Cards) != 2 {
+		t.Errorf("Expected hand size 2, got %d", len(player.Hand().Cards))
 	}
-	if player.State != BlackJack {
-		t.Errorf("Expected state BlackJack, got %v", player.State)
+	if player.Hand().State != BlackJack {
+		t.Errorf("Expected state BlackJack, got %v", player.Hand().State)
 	}
 
 	// Create a new player for bust test
@@ -58,8 +59,8 @@ func TestAddCard(t *testing.T) {
 	bustPlayer.AddCard(card4) // 20
 	bustPlayer.AddCard(card5) // 30 (bust)
 
-	if bustPlayer.State != Busted {
-		t.Errorf("Expected state Busted, got %v", bustPlayer.State)
+	if bustPlayer.Hand().State != Busted {
+		t.Errorf("Expected state Busted, got %v", bustPlayer.Hand().State)
 	}
 }
 
@@ -117,8 +118,8 @@ func TestStand(t *testing.T) {
 	player := NewPlayer("Test")
 	player.Stand()
 
-	if player.State != Standing {
-		t.Errorf("Expected state Standing, got %v", player.State)
+	if player.Hand().State != Standing {
+		t.Errorf("Expected state Standing, got %v", player.Hand().State)
 	}
 }
 
@@ -133,10 +134,10 @@ func TestClearHand(t *testing.T) {
 	// Clear hand
 	player.ClearHand()
 
-	if len(player.Hand) != 0 {
+	if len(player.Hand().Cards) != 0 {
 		t.Error("Expected empty hand after clear")
 	}
-	if player.State != Playing {
+	if player.Hand().State != Playing {
 		t.Error("Expected state to reset to Playing")
 	}
 }
@@ -267,8 +268,8 @@ func TestStateTransitions(t *testing.T) {
 			player := NewPlayer("Test")
 			test.actions(player)
 
-			if player.State != test.expectedState {
-				t.Errorf("Expected state %v, got %v", test.expectedState, player.State)
+			if player.Hand().State != test.expectedState {
+				t.Errorf("Expected state %v, got %v", test.expectedState, player.Hand().State)
 			}
 			if player.GetHandValue() != test.expectedValue {
 				t.Errorf("Expected hand value %d, got %d", test.expectedValue, player.GetHandValue())
@@ -458,10 +459,10 @@ func TestClearHandAfterStates(t *testing.T) {
 			test.setupState(player)
 			player.ClearHand()
 
-			if player.State != Playing {
-				t.Errorf("Expected state Playing after clear, got %v", player.State)
+			if player.Hand().State != Playing {
+				t.Errorf("Expected state Playing after clear, got %v", player.Hand().State)
 			}
-			if len(player.Hand) != 0 {
+			if len(player.Hand().Cards) != 0 {
 				t.Error("Expected empty hand after clear")
 			}
 		})
@@ -476,3 +477,88 @@ func mustCreateCard(t *testing.T, suit deck.Suit, rank deck.Rank) deck.Card {
 	}
 	return card
 }
+
+// TestPlayerJSON tests saving and restoring a player
+func TestPlayerJSON(t *testing.T) {
+	player := NewPlayer("Test")
+	card1, _ := deck.NewCard(deck.Hearts, deck.Ten)
+	card2, _ := deck.NewCard(deck.Spades, deck.Seven)
+	player.AddCard(card1)
+	player.AddCard(card2)
+	player.Stand()
+
+	data, err := json.Marshal(player)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `{"name":"Test","hands":[{"cards":["TH","7S"],"state":"Standing"}]}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	restored := &Player{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if restored.Name != "Test" || restored.Hand().State != Standing || restored.GetHandValue() != 17 {
+		t.Errorf("Unexpected restored player %+v", restored)
+	}
+
+	if err := json.Unmarshal([]byte(`{"hands":[{"state":"Sleeping"}]}`), restored); err == nil {
+		t.Error("Expected error for unknown state")
+	}
+	if _, err := json.Marshal(&Player{Hands: []*Hand{{State: PlayerState(42)}}}); err == nil {
+		t.Error("Expected error for invalid state")
+	}
+}
+
+// TestSplit tests splitting a pair into two hands
+func TestSplit(t *testing.T) {
+	player := NewPlayer("Test")
+	player.AddCard(mustCreateCard(t, deck.Hearts, deck.Eight))
+	player.AddCard(mustCreateCard(t, deck.Spades, deck.Eight))
+	player.Hand().Bet = 10
+
+	second, err := player.Split()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(player.Hands) != 2 || player.Splits() != 1 || player.Hands[1] != second {
+		t.Fatalf("Expected two hands after one split, got %d", len(player.Hands))
+	}
+	for i, hand := range player.Hands {
+		if len(hand.Cards) != 1 || hand.Cards[0].Rank != deck.Eight || hand.Bet != 10 || !hand.Split {
+			t.Errorf("Hand %d: expected one Eight with a bet of 10, got %+v", i+1, hand)
+		}
+	}
+
+	// A new split is inserted after the hand being played
+	player.AddCard(mustCreateCard(t, deck.Diamonds, deck.Eight))
+	third, _ := player.Split()
+	if player.Hands[1] != third || player.Hands[2] != second {
+		t.Error("Expected the new hand to be played before the earlier one")
+	}
+
+	for want := 1; want < 3; want++ {
+		if !player.NextHand() || player.Active != want {
+			t.Fatalf("Expected to move to hand %d", want+1)
+		}
+	}
+	if player.NextHand() {
+		t.Error("Expected no hands left to play")
+	}
+	if !strings.Contains(player.String(), "Hand 3 (playing):") {
+		t.Errorf("Expected every hand listed, got %q", player.String())
+	}
+
+	player.ClearHand()
+	if len(player.Hands) != 1 || player.Active != 0 {
+		t.Error("Expected a single hand after clearing")
+	}
+
+	player.AddCard(mustCreateCard(t, deck.Hearts, deck.Eight))
+	player.AddCard(mustCreateCard(t, deck.Spades, deck.Nine))
+	if _, err := player.Split(); err == nil {
+		t.Error("Expected error splitting a non-pair")
+	}
+}
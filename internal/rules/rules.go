@@ -1,7 +1,10 @@
 // Package rules provides game rules and help text for BlackJack
 package rules
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Section represents a section of rules or help text
 type Section struct {
@@ -9,8 +12,107 @@ type Section struct {
 	Content string
 }
 
-// GetGameRules returns all game rules sections
-func GetGameRules() []Section {
+// dealerRuleText describes when the dealer draws under the given rules
+func dealerRuleText(r TableRules) string {
+	if r.DealerHitsSoft17 {
+		return "Dealer must hit on 16 or below and on soft 17, and stand on hard 17 or above"
+	}
+	return "Dealer must hit on 16 or below, and stand on 17 or above (including soft 17)"
+}
+
+// tableRulesText lists the rules in force at the table, one per line
+func tableRulesText(r TableRules) string {
+	lines := []string{
+		"• " + shoeRuleText(r),
+		"• " + dealerRuleText(r),
+		"• " + doubleRuleText(r.Double),
+		"• " + splitRuleText(r),
+		"• " + surrenderRuleText(r.Surrender),
+		"• " + peekRuleText(r),
+		fmt.Sprintf("• BlackJack pays %s, other wins pay 1:1, a push returns your bet", r.BlackjackPayout),
+		"• Insurance of up to half your bet pays 2:1 against a dealer BlackJack; even money is offered on a BlackJack",
+		"• " + betRuleText(r),
+	}
+	return strings.Join(lines, "\n")
+}
+
+// doubleRuleText describes which hands may be doubled
+func doubleRuleText(d DoubleRule) string {
+	switch d {
+	case DoubleNineToEleven:
+		return "Double down on a total of 9, 10 or 11 only"
+	case DoubleTenToEleven:
+		return "Double down on a total of 10 or 11 only"
+	default:
+		return "Double down on any first two cards"
+	}
+}
+
+// splitRuleText describes how many times pairs may be split and what split hands may do
+func splitRuleText(r TableRules) string {
+	if r.MaxSplits == 0 {
+		return "Pairs may not be split"
+	}
+	text := fmt.Sprintf("Split pairs into up to %d hands; split Aces get one card each", r.MaxSplits+1)
+	if r.ResplitAces {
+		text += " and may be split again"
+	}
+	if r.DoubleAfterSplit {
+		return text + "; double after splitting allowed"
+	}
+	return text + "; no doubling after splitting"
+}
+
+// surrenderRuleText describes whether and when a hand may be surrendered
+func surrenderRuleText(s SurrenderRule) string {
+	switch s {
+	case LateSurrender:
+		return "Late surrender: give up half your bet on your first decision, unless the dealer has BlackJack"
+	case EarlySurrender:
+		return "Early surrender: give up half your bet on your first decision, even against a dealer BlackJack"
+	default:
+		return "Surrender is not offered"
+	}
+}
+
+// peekRuleText describes when the dealer's BlackJack is revealed
+func peekRuleText(r TableRules) string {
+	switch {
+	case r.DealerPeeks && r.Surrender == EarlySurrender:
+		return "Dealer checks for BlackJack under an Ace or ten once every player has had the chance to surrender, and a dealer BlackJack then takes only the original bets"
+	case r.DealerPeeks:
+		return "Dealer checks for BlackJack under an Ace or ten, and a dealer BlackJack ends the round at once"
+	}
+	return "No hole card: the dealer takes a second card after you play, and a dealer BlackJack takes every bet, including doubles and splits"
+}
+
+// betRuleText describes the table limits, or the single stake when they are the same
+func betRuleText(r TableRules) string {
+	if r.MinBet == r.MaxBet {
+		return fmt.Sprintf("Every bet is %d chips", r.MinBet)
+	}
+	return fmt.Sprintf("Bets from %d to %d chips", r.MinBet, r.MaxBet)
+}
+
+// shoeRuleText describes the size of the shoe and when it is reshuffled
+// A penetration of zero means there is no cut card
+func shoeRuleText(r TableRules) string {
+	if r.Penetration == 0 {
+		return deckCountText(r.Decks) + ", dealt until too few cards are left for the next round"
+	}
+	return fmt.Sprintf("%s, reshuffled after %.0f%% of the shoe is dealt", deckCountText(r.Decks), r.Penetration)
+}
+
+// deckCountText describes the size of the shoe
+func deckCountText(decks int) string {
+	if decks == 1 {
+		return "Single deck"
+	}
+	return fmt.Sprintf("%d decks", decks)
+}
+
+// GetGameRules returns all game rules sections for the given table rules
+func GetGameRules(table TableRules) []Section {
 	return []Section{
 		{
 			Title: "Game Objective",
@@ -29,12 +131,20 @@ func GetGameRules() []Section {
 			Title: "Game Flow",
 			Content: `1. You and the dealer each get two cards
 2. One of dealer's cards remains hidden until your turn ends
+   If the dealer shows an Ace you may first take insurance
+   (or even money if you hold a BlackJack)
 3. You can repeatedly choose to:
    • Hit - Take another card
    • Stand - Keep your current hand
+   On your first two cards you may also:
+   • Double - Double your bet, take exactly one more card and stand
+   • Split - Split a pair into two hands, each with its own bet
+     (21 on a split hand is not a BlackJack)
+   • Surrender - Give up the hand and half your bet, as your first decision,
+     if the table offers it
 4. If you go over 21, you bust and lose
 5. If you stand, the dealer reveals their hidden card
-6. Dealer must hit on 16 or below, and stand on 17 or above`,
+6. ` + dealerRuleText(table),
 		},
 		{
 			Title: "Winning Conditions",
@@ -50,19 +160,46 @@ You lose if:
 
 If both hands are equal, it's a tie (Push)`,
 		},
+		{
+			Title:   "Table Rules",
+			Content: tableRulesText(table),
+		},
 	}
 }
 
-// GetCommandHelp returns help text for game commands
-func GetCommandHelp() []Section {
+// Actions lists which optional player actions are legal right now
+type Actions struct {
+	Double    bool // The hand may be doubled
+	Split     bool // The hand is a pair that may be split
+	Surrender bool // The hand may be surrendered for half the bet
+}
+
+// GetCommandHelp returns help text for game commands, listing optional
+// actions such as double, split and surrender only when they are legal
+func GetCommandHelp(legal Actions) []Section {
+	lines := []string{
+		"Available commands during play:",
+		"• h or hit    - Take another card",
+		"• s or stand  - Keep your current hand",
+	}
+	if legal.Double {
+		lines = append(lines, "• d or double - Double your bet and take one final card")
+	}
+	if legal.Split {
+		lines = append(lines, "• p or split  - Split your pair into two hands")
+	}
+	if legal.Surrender {
+		lines = append(lines, "• u or surrender - Give up the hand and get half your bet back")
+	}
+	lines = append(lines,
+		"• ? or hint   - Show the basic strategy play for your hand",
+		"• r or rules  - Display game rules",
+		"• q or quit   - Exit the game")
+
 	return []Section{
 		{
-			Title: "Game Commands",
-			Content: `Available commands during play:
-• h or hit   - Take another card
-• s or stand - Keep your current hand
-• r or rules - Display game rules
-• q or quit  - Exit the game`,
+			Title:   "Game Commands",
+			Content: strings.Join(lines, "\n"),
 		},
 	}
 }
@@ -72,24 +209,24 @@ func DisplaySection(section Section) string {
 	return fmt.Sprintf("\n=== %s ===\n%s\n", section.Title, section.Content)
 }
 
-// DisplayAllRules formats and returns the complete rules text
-func DisplayAllRules() string {
+// DisplayAllRules formats and returns the complete rules text for the given table rules
+func DisplayAllRules(table TableRules) string {
 	var result string
 	result += "\n=== BLACKJACK RULES ===\n"
 
-	for _, section := range GetGameRules() {
+	for _, section := range GetGameRules(table) {
 		result += DisplaySection(section)
 	}
 
 	return result
 }
 
-// DisplayHelp formats and returns the command help text
-func DisplayHelp() string {
+// DisplayHelp formats and returns the command help text for the legal actions
+func DisplayHelp(legal Actions) string {
 	var result string
 	result += "\n=== GAME HELP ===\n"
 
-	for _, section := range GetCommandHelp() {
+	for _, section := range GetCommandHelp(legal) {
 		result += DisplaySection(section)
 	}
 
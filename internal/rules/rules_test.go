@@ -7,7 +7,7 @@ import (
 
 // TestGetGameRules tests the game rules content
 func TestGetGameRules(t *testing.T) {
-	rules := GetGameRules()
+	rules := GetGameRules(DefaultTableRules())
 
 	// Check that we have all required sections
 	expectedTitles := []string{
@@ -15,6 +15,7 @@ func TestGetGameRules(t *testing.T) {
 		"Card Values",
 		"Game Flow",
 		"Winning Conditions",
+		"Table Rules",
 	}
 
 	if len(rules) != len(expectedTitles) {
@@ -32,9 +33,135 @@ func TestGetGameRules(t *testing.T) {
 	}
 }
 
+// TestDealerRuleText tests that the rules text follows the soft 17 rule
+func TestDealerRuleText(t *testing.T) {
+	table := DefaultTableRules()
+	s17 := DisplayAllRules(table)
+	if !strings.Contains(s17, "stand on 17 or above (including soft 17)") {
+		t.Error("S17 rules should say the dealer stands on soft 17")
+	}
+
+	table.DealerHitsSoft17 = true
+	h17 := DisplayAllRules(table)
+	if !strings.Contains(h17, "hit on 16 or below and on soft 17") {
+		t.Error("H17 rules should say the dealer hits soft 17")
+	}
+}
+
+// TestSplitRuleText tests that the rules text follows the split rules
+func TestSplitRuleText(t *testing.T) {
+	table := DefaultTableRules()
+	if text := DisplayAllRules(table); !strings.Contains(text, "up to 4 hands") || !strings.Contains(text, "double after splitting allowed") {
+		t.Error("Default rules should allow four hands and doubling after a split")
+	}
+
+	table.ResplitAces = true
+	table.DoubleAfterSplit = false
+	text := DisplayAllRules(table)
+	if !strings.Contains(text, "may be split again") || !strings.Contains(text, "no doubling after splitting") {
+		t.Error("Rules should mention resplitting Aces and no doubling after splits")
+	}
+
+	table.MaxSplits = 0
+	if !strings.Contains(DisplayAllRules(table), "Pairs may not be split") {
+		t.Error("Rules should say when pairs may not be split")
+	}
+}
+
+// TestSurrenderRuleText tests that the rules text follows the surrender rule
+func TestSurrenderRuleText(t *testing.T) {
+	tests := map[SurrenderRule]string{
+		NoSurrender:    "Surrender is not offered",
+		LateSurrender:  "Late surrender",
+		EarlySurrender: "Early surrender",
+	}
+	for rule, want := range tests {
+		table := DefaultTableRules()
+		table.Surrender = rule
+		if !strings.Contains(DisplayAllRules(table), want) {
+			t.Errorf("Rules with %s surrender should contain %q", rule, want)
+		}
+	}
+}
+
+// TestPeekRuleText tests that the rules text follows the peek and surrender rules
+func TestPeekRuleText(t *testing.T) {
+	tests := []struct {
+		name      string
+		peeks     bool
+		surrender SurrenderRule
+		want      string
+	}{
+		{"Peek", true, LateSurrender, "a dealer BlackJack ends the round at once"},
+		{"Peek after early surrender", true, EarlySurrender, "once every player has had the chance to surrender"},
+		{"No hole card", false, LateSurrender, "No hole card"},
+		{"No hole card with early surrender", false, EarlySurrender, "No hole card"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := DefaultTableRules()
+			table.DealerPeeks = test.peeks
+			table.Surrender = test.surrender
+			if text := peekRuleText(table); !strings.Contains(text, test.want) {
+				t.Errorf("Expected %q in %q", test.want, text)
+			}
+			if text := DisplayAllRules(table); !strings.Contains(text, test.want) {
+				t.Errorf("Expected the rules to contain %q", test.want)
+			}
+		})
+	}
+}
+
+// TestShoeRuleText tests that the rules text follows the shoe size and cut card
+func TestShoeRuleText(t *testing.T) {
+	tests := []struct {
+		decks       int
+		penetration float64
+		want        string
+	}{
+		{1, 75, "Single deck, reshuffled after 75% of the shoe is dealt"},
+		{6, 80, "6 decks, reshuffled after 80% of the shoe is dealt"},
+		{2, 0, "2 decks, dealt until too few cards are left for the next round"},
+	}
+	for _, test := range tests {
+		table := DefaultTableRules()
+		table.Decks = test.decks
+		table.Penetration = test.penetration
+		if text := shoeRuleText(table); text != test.want {
+			t.Errorf("Expected %q, got %q", test.want, text)
+		}
+	}
+}
+
+// TestDealerHits tests when the dealer draws under S17 and H17
+func TestDealerHits(t *testing.T) {
+	tests := []struct {
+		total int
+		soft  bool
+		s17   bool
+		h17   bool
+	}{
+		{16, false, true, true},
+		{16, true, true, true},
+		{17, false, false, false},
+		{17, true, false, true},
+		{18, true, false, false},
+		{21, false, false, false},
+	}
+
+	for _, test := range tests {
+		if got := (TableRules{}).DealerHits(test.total, test.soft); got != test.s17 {
+			t.Errorf("S17, total %d soft %t: expected hit=%t, got %t", test.total, test.soft, test.s17, got)
+		}
+		if got := (TableRules{DealerHitsSoft17: true}).DealerHits(test.total, test.soft); got != test.h17 {
+			t.Errorf("H17, total %d soft %t: expected hit=%t, got %t", test.total, test.soft, test.h17, got)
+		}
+	}
+}
+
 // TestGetCommandHelp tests the command help content
 func TestGetCommandHelp(t *testing.T) {
-	help := GetCommandHelp()
+	help := GetCommandHelp(Actions{})
 
 	if len(help) == 0 {
 		t.Error("Expected command help sections, got none")
@@ -45,6 +172,7 @@ func TestGetCommandHelp(t *testing.T) {
 	requiredCommands := []string{
 		"hit",
 		"stand",
+		"hint",
 		"rules",
 		"quit",
 	}
@@ -56,6 +184,28 @@ func TestGetCommandHelp(t *testing.T) {
 	}
 }
 
+// TestCommandHelpLegalActions tests that optional commands are listed only when legal
+func TestCommandHelpLegalActions(t *testing.T) {
+	if strings.Contains(GetCommandHelp(Actions{})[0].Content, "double") {
+		t.Error("Double should not be listed when it is not legal")
+	}
+	if !strings.Contains(GetCommandHelp(Actions{Double: true})[0].Content, "d or double") {
+		t.Error("Double should be listed when it is legal")
+	}
+	if strings.Contains(GetCommandHelp(Actions{Double: true})[0].Content, "split") {
+		t.Error("Split should not be listed when it is not legal")
+	}
+	if !strings.Contains(GetCommandHelp(Actions{Split: true})[0].Content, "p or split") {
+		t.Error("Split should be listed when it is legal")
+	}
+	if strings.Contains(GetCommandHelp(Actions{Split: true})[0].Content, "surrender") {
+		t.Error("Surrender should not be listed when it is not legal")
+	}
+	if !strings.Contains(GetCommandHelp(Actions{Surrender: true})[0].Content, "u or surrender") {
+		t.Error("Surrender should be listed when it is legal")
+	}
+}
+
 // TestDisplaySection tests section formatting
 func TestDisplaySection(t *testing.T) {
 	section := Section{
@@ -80,7 +230,7 @@ func TestDisplaySection(t *testing.T) {
 
 // TestDisplayAllRules tests complete rules display
 func TestDisplayAllRules(t *testing.T) {
-	rules := DisplayAllRules()
+	rules := DisplayAllRules(DefaultTableRules())
 
 	// Check main header
 	if !strings.Contains(rules, "BLACKJACK RULES") {
@@ -88,7 +238,7 @@ func TestDisplayAllRules(t *testing.T) {
 	}
 
 	// Check that all sections are included
-	for _, section := range GetGameRules() {
+	for _, section := range GetGameRules(DefaultTableRules()) {
 		if !strings.Contains(rules, section.Title) {
 			t.Errorf("Rules display missing section %q", section.Title)
 		}
@@ -100,7 +250,7 @@ func TestDisplayAllRules(t *testing.T) {
 
 // TestDisplayHelp tests help text display
 func TestDisplayHelp(t *testing.T) {
-	help := DisplayHelp()
+	help := DisplayHelp(Actions{})
 
 	// Check main header
 	if !strings.Contains(help, "GAME HELP") {
@@ -108,7 +258,7 @@ func TestDisplayHelp(t *testing.T) {
 	}
 
 	// Check that command section is included
-	commandHelp := GetCommandHelp()[0]
+	commandHelp := GetCommandHelp(Actions{})[0]
 	if !strings.Contains(help, commandHelp.Title) {
 		t.Error("Help display missing command section")
 	}
@@ -0,0 +1,192 @@
+package rules
+
+import (
+	"blackjack/internal/deck"
+	"fmt"
+)
+
+// Payout is what a BlackJack pays, as a ratio of the bet
+type Payout int
+
+const (
+	ThreeToTwo Payout = iota // 3:2, the traditional payout
+	SixToFive                // 6:5, common at low-limit tables
+	EvenMoney                // 1:1, the same as any other win
+)
+
+// DoubleRule restricts which two-card hands may be doubled
+type DoubleRule int
+
+const (
+	DoubleAnyTwo       DoubleRule = iota // Double on any first two cards
+	DoubleNineToEleven                   // Double only on totals of 9, 10 or 11
+	DoubleTenToEleven                    // Double only on totals of 10 or 11
+)
+
+// SurrenderRule says whether, and when, a player may give up half their bet
+type SurrenderRule int
+
+const (
+	NoSurrender    SurrenderRule = iota // Surrender is not offered
+	LateSurrender                       // Surrender after the dealer checks for BlackJack
+	EarlySurrender                      // Surrender before the dealer checks for BlackJack
+)
+
+// payoutNames, doubleRuleNames and surrenderRuleNames hold the names used when
+// printing and saving the rule options
+var (
+	payoutNames        = map[Payout]string{ThreeToTwo: "3:2", SixToFive: "6:5", EvenMoney: "1:1"}
+	doubleRuleNames    = map[DoubleRule]string{DoubleAnyTwo: "any", DoubleNineToEleven: "9-11", DoubleTenToEleven: "10-11"}
+	surrenderRuleNames = map[SurrenderRule]string{NoSurrender: "none", LateSurrender: "late", EarlySurrender: "early"}
+)
+
+// TableRules holds the rules in force at a table
+// The game enforces these rules and the help text is rendered from them
+type TableRules struct {
+	Decks            int           `json:"decks"`            // Number of decks in the shoe (1, 2, 4, 6 or 8)
+	Penetration      float64       `json:"penetration"`      // Percentage of the shoe dealt before the cut card comes out
+	DealerHitsSoft17 bool          `json:"dealerHitsSoft17"` // H17: dealer hits soft 17 (otherwise stands, S17)
+	BlackjackPayout  Payout        `json:"blackjackPayout"`  // What a player's BlackJack pays
+	Double           DoubleRule    `json:"double"`           // Which hands may be doubled
+	DoubleAfterSplit bool          `json:"doubleAfterSplit"` // DAS: split hands may be doubled
+	Surrender        SurrenderRule `json:"surrender"`        // Whether and when surrender is offered
+	MaxSplits        int           `json:"maxSplits"`        // Splits allowed per round (3 means up to four hands)
+	ResplitAces      bool          `json:"resplitAces"`      // RSA: split Aces may be split again
+	DealerPeeks      bool          `json:"dealerPeeks"`      // Dealer checks for BlackJack under an Ace or ten before players act (otherwise no hole card, ENHC)
+	MinBet           int           `json:"minBet"`           // Smallest bet accepted, in chips
+	MaxBet           int           `json:"maxBet"`           // Largest bet accepted, in chips
+}
+
+// DefaultTableRules returns the rules used when none are given
+func DefaultTableRules() TableRules {
+	return TableRules{
+		Decks:            1,
+		Penetration:      deck.DefaultPenetration,
+		DealerHitsSoft17: false,
+		BlackjackPayout:  ThreeToTwo,
+		Double:           DoubleAnyTwo,
+		DoubleAfterSplit: true,
+		Surrender:        NoSurrender,
+		MaxSplits:        3,
+		ResplitAces:      false,
+		DealerPeeks:      true,
+		MinBet:           10,
+		MaxBet:           500,
+	}
+}
+
+// Validate checks that every rule has a value the game can enforce
+func (r TableRules) Validate() error {
+	switch {
+	case !deck.ValidDeckCount(r.Decks):
+		return fmt.Errorf("invalid number of decks: %d (must be 1, 2, 4, 6 or 8)", r.Decks)
+	case r.Penetration < 0 || r.Penetration >= 100:
+		return fmt.Errorf("invalid penetration: %v%% (must be between 0 and 100)", r.Penetration)
+	case payoutNames[r.BlackjackPayout] == "":
+		return fmt.Errorf("invalid BlackJack payout: %d", int(r.BlackjackPayout))
+	case doubleRuleNames[r.Double] == "":
+		return fmt.Errorf("invalid double rule: %d", int(r.Double))
+	case surrenderRuleNames[r.Surrender] == "":
+		return fmt.Errorf("invalid surrender rule: %d", int(r.Surrender))
+	case r.MaxSplits < 0:
+		return fmt.Errorf("invalid maximum splits: %d", r.MaxSplits)
+	case r.MinBet < 1 || r.MaxBet < r.MinBet:
+		return fmt.Errorf("invalid betting limits: %d to %d", r.MinBet, r.MaxBet)
+	}
+	return nil
+}
+
+// DealerHits reports whether the dealer must take another card on the given total
+func (r TableRules) DealerHits(total int, soft bool) bool {
+	if total == 17 && soft {
+		return r.DealerHitsSoft17
+	}
+	return total < 17
+}
+
+// Pay returns the winnings for a BlackJack on the given bet, rounded down to whole chips
+func (p Payout) Pay(bet int) int {
+	win, per := p.Ratio()
+	return bet * win / per
+}
+
+// Ratio returns the payout as winnings per amount bet (e.g., 3, 2 for 3:2)
+func (p Payout) Ratio() (win, bet int) {
+	switch p {
+	case SixToFive:
+		return 6, 5
+	case EvenMoney:
+		return 1, 1
+	default:
+		return 3, 2
+	}
+}
+
+// Allows reports whether a two-card hand with the given total may be doubled
+func (d DoubleRule) Allows(total int) bool {
+	switch d {
+	case DoubleNineToEleven:
+		return total >= 9 && total <= 11
+	case DoubleTenToEleven:
+		return total >= 10 && total <= 11
+	default:
+		return true
+	}
+}
+
+// String returns the payout as a ratio (e.g., "3:2")
+func (p Payout) String() string { return nameOf(payoutNames, p) }
+
+// String returns the name of the double rule (e.g., "9-11")
+func (d DoubleRule) String() string { return nameOf(doubleRuleNames, d) }
+
+// String returns the name of the surrender rule (e.g., "late")
+func (s SurrenderRule) String() string { return nameOf(surrenderRuleNames, s) }
+
+// MarshalText encodes the payout as a ratio so saved rules stay readable
+func (p Payout) MarshalText() ([]byte, error) { return marshalName(payoutNames, p) }
+
+// UnmarshalText decodes a payout ratio written by MarshalText
+func (p *Payout) UnmarshalText(text []byte) error { return unmarshalName(payoutNames, p, text) }
+
+// MarshalText encodes the double rule by name
+func (d DoubleRule) MarshalText() ([]byte, error) { return marshalName(doubleRuleNames, d) }
+
+// UnmarshalText decodes a double rule name written by MarshalText
+func (d *DoubleRule) UnmarshalText(text []byte) error { return unmarshalName(doubleRuleNames, d, text) }
+
+// MarshalText encodes the surrender rule by name
+func (s SurrenderRule) MarshalText() ([]byte, error) { return marshalName(surrenderRuleNames, s) }
+
+// UnmarshalText decodes a surrender rule name written by MarshalText
+func (s *SurrenderRule) UnmarshalText(text []byte) error {
+	return unmarshalName(surrenderRuleNames, s, text)
+}
+
+// nameOf looks up the name of a rule option
+func nameOf[T comparable](names map[T]string, v T) string {
+	if name, ok := names[v]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// marshalName encodes a rule option by name, rejecting unknown values
+func marshalName[T comparable](names map[T]string, v T) ([]byte, error) {
+	name, ok := names[v]
+	if !ok {
+		return nil, fmt.Errorf("invalid rule option: %v", v)
+	}
+	return []byte(name), nil
+}
+
+// unmarshalName decodes a rule option from its name
+func unmarshalName[T comparable](names map[T]string, v *T, text []byte) error {
+	for option, name := range names {
+		if name == string(text) {
+			*v = option
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid rule option: %q", text)
+}
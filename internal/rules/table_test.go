@@ -0,0 +1,150 @@
+package rules
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestValidate tests checking table rules
+func TestValidate(t *testing.T) {
+	if err := DefaultTableRules().Validate(); err != nil {
+		t.Fatalf("Default rules should be valid: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*TableRules)
+	}{
+		{"Three decks", func(r *TableRules) { r.Decks = 3 }},
+		{"No decks", func(r *TableRules) { r.Decks = 0 }},
+		{"Negative penetration", func(r *TableRules) { r.Penetration = -5 }},
+		{"Full penetration", func(r *TableRules) { r.Penetration = 100 }},
+		{"Unknown payout", func(r *TableRules) { r.BlackjackPayout = Payout(9) }},
+		{"Unknown double rule", func(r *TableRules) { r.Double = DoubleRule(9) }},
+		{"Unknown surrender rule", func(r *TableRules) { r.Surrender = SurrenderRule(9) }},
+		{"Negative splits", func(r *TableRules) { r.MaxSplits = -1 }},
+		{"No minimum bet", func(r *TableRules) { r.MinBet = 0 }},
+		{"Maximum below minimum", func(r *TableRules) { r.MaxBet = r.MinBet - 1 }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := DefaultTableRules()
+			test.change(&table)
+			if err := table.Validate(); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+// TestPayoutRatio tests the BlackJack payout ratios
+func TestPayoutRatio(t *testing.T) {
+	tests := []struct {
+		payout   Payout
+		win, bet int
+		name     string
+	}{
+		{ThreeToTwo, 3, 2, "3:2"},
+		{SixToFive, 6, 5, "6:5"},
+		{EvenMoney, 1, 1, "1:1"},
+	}
+	for _, test := range tests {
+		win, bet := test.payout.Ratio()
+		if got := test.payout.Pay(10); got != 10*test.win/test.bet {
+			t.Errorf("%s on 10 chips: expected %d, got %d", test.name, 10*test.win/test.bet, got)
+		}
+		if win != test.win || bet != test.bet || test.payout.String() != test.name {
+			t.Errorf("Expected %s, got %d:%d (%s)", test.name, win, bet, test.payout)
+		}
+	}
+}
+
+// TestDoubleRuleAllows tests which totals each double rule allows
+func TestDoubleRuleAllows(t *testing.T) {
+	for total := 4; total <= 21; total++ {
+		if !DoubleAnyTwo.Allows(total) {
+			t.Errorf("Double any two should allow %d", total)
+		}
+		if got, want := DoubleNineToEleven.Allows(total), total >= 9 && total <= 11; got != want {
+			t.Errorf("Double 9-11 on %d: expected %t, got %t", total, want, got)
+		}
+		if got, want := DoubleTenToEleven.Allows(total), total == 10 || total == 11; got != want {
+			t.Errorf("Double 10-11 on %d: expected %t, got %t", total, want, got)
+		}
+	}
+}
+
+// TestTableRulesJSON tests that rules are saved with readable option names
+func TestTableRulesJSON(t *testing.T) {
+	table := DefaultTableRules()
+	table.Decks = 6
+	table.BlackjackPayout = SixToFive
+	table.Double = DoubleTenToEleven
+	table.Surrender = LateSurrender
+
+	data, err := json.Marshal(table)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{`"blackjackPayout":"6:5"`, `"double":"10-11"`, `"surrender":"late"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %s in %s", want, data)
+		}
+	}
+
+	var restored TableRules
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if restored != table {
+		t.Errorf("Expected %+v, got %+v", table, restored)
+	}
+
+	if err := json.Unmarshal([]byte(`{"surrender":"sometimes"}`), &restored); err == nil {
+		t.Error("Expected error for unknown surrender rule")
+	}
+	if got := ThreeToTwo.Pay(15); got != 22 {
+		t.Errorf("Expected 3:2 on 15 chips to round down to 22, got %d", got)
+	}
+	if _, err := json.Marshal(TableRules{BlackjackPayout: Payout(9)}); err == nil {
+		t.Error("Expected error saving an unknown payout")
+	}
+}
+
+// TestTableRulesText tests that the help text follows the table rules
+func TestTableRulesText(t *testing.T) {
+	table := DefaultTableRules()
+	text := DisplayAllRules(table)
+	if !strings.Contains(text, "Single deck, reshuffled after 75% of the shoe is dealt") {
+		t.Errorf("Expected single deck description, got %s", text)
+	}
+
+	if !strings.Contains(text, "BlackJack pays 3:2") || !strings.Contains(text, "Bets from 10 to 500 chips") {
+		t.Errorf("Expected payout and betting limits, got %s", text)
+	}
+
+	table.Decks = 6
+	table.Penetration = 80
+	table.BlackjackPayout = SixToFive
+	text = DisplayAllRules(table)
+	if !strings.Contains(text, "6 decks, reshuffled after 80% of the shoe is dealt") {
+		t.Errorf("Expected 6-deck description, got %s", text)
+	}
+	if !strings.Contains(text, "Double down on any first two cards") {
+		t.Errorf("Expected double rule, got %s", text)
+	}
+	table.Double = DoubleNineToEleven
+	if text := DisplayAllRules(table); !strings.Contains(text, "Double down on a total of 9, 10 or 11 only") {
+		t.Errorf("Expected 9-11 double rule, got %s", text)
+	}
+	if !strings.Contains(text, "BlackJack pays 6:5") {
+		t.Errorf("Expected 6:5 payout, got %s", text)
+	}
+
+	table.MinBet, table.MaxBet = 100, 100
+	if text := DisplayAllRules(table); !strings.Contains(text, "Every bet is 100 chips") || strings.Contains(text, "Bets from") {
+		t.Errorf("Expected a single stake when the limits are equal, got %s", text)
+	}
+}
@@ -0,0 +1,356 @@
+// Package sim plays BlackJack rounds headlessly to measure what a strategy and set of rules are worth
+package sim
+
+import (
+	"blackjack/internal/count"
+	"blackjack/internal/game"
+	"blackjack/internal/rules"
+	"blackjack/internal/strategy"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Unit is the flat bet placed on every simulated round, in chips
+// It divides evenly for every BlackJack payout and for surrender
+const Unit = 100
+
+// Strategy chooses the action for the hand being played
+// The game's Counter holds the count of every card seen so far in the shoe
+type Strategy func(g *game.Game) (strategy.Action, error)
+
+// Strategies holds the strategies the simulator can play, by name
+var Strategies = map[string]Strategy{
+	"basic":      BasicStrategy,
+	"dealer":     MimicDealer,
+	"never-bust": NeverBust,
+}
+
+// StrategyNames returns the names of the available strategies in order
+func StrategyNames() []string {
+	names := make([]string, 0, len(Strategies))
+	for name := range Strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BasicStrategy plays the basic strategy chart for the table rules
+func BasicStrategy(g *game.Game) (strategy.Action, error) {
+	return g.Hint()
+}
+
+// MimicDealer hits and stands the way the dealer must
+func MimicDealer(g *game.Game) (strategy.Action, error) {
+	value := g.Seats()[g.Turn()].Player.Evaluate()
+	if g.Rules().DealerHits(value.Total, value.Soft) {
+		return strategy.Hit, nil
+	}
+	return strategy.Stand, nil
+}
+
+// NeverBust only takes a card when no card can bust the hand
+func NeverBust(g *game.Game) (strategy.Action, error) {
+	value := g.Seats()[g.Turn()].Player.Evaluate()
+	if value.Total <= 11 || (value.Soft && value.Total < 18) {
+		return strategy.Hit, nil
+	}
+	return strategy.Stand, nil
+}
+
+// Config describes a simulation
+type Config struct {
+	Rules    rules.TableRules
+	Strategy Strategy
+	Rounds   int   // Rounds to play in total
+	Workers  int   // Games played side by side; 0 uses every CPU core
+	Seed     int64 // Worker i shuffles with Seed+i, so a run can be repeated exactly
+
+	// Count, if set, breaks the results down by the true count before each deal
+	Count count.System
+}
+
+// maxTrueCount is the furthest from zero the true count breakdown goes;
+// rounds beyond it are tallied with the last count on that side
+const maxTrueCount = 6
+
+// Tally holds the totals of the rounds dealt at one true count
+type Tally struct {
+	Rounds     int
+	Net        float64
+	SumSquares float64
+}
+
+// result returns the tally as a Result, for its expected value and interval
+func (t Tally) result() Result {
+	return Result{Rounds: t.Rounds, Net: t.Net, SumSquares: t.SumSquares}
+}
+
+// Result holds the totals from a simulation
+// Every round starts with a bet of one unit; nets are measured in those units
+type Result struct {
+	Rounds     int
+	Net        float64 // Total won (or lost, if negative)
+	SumSquares float64 // Sum of each round's squared net, for the variance
+	Wins       int     // Rounds that ended ahead
+	Losses     int     // Rounds that ended behind
+	Pushes     int     // Rounds that broke even
+	Blackjacks int     // Rounds dealt a BlackJack
+	Workers    int
+	Seed       int64
+
+	Count       string                    // Name of the counting system the breakdown uses, if any
+	ByTrueCount [2*maxTrueCount + 1]Tally // Rounds by true count before the deal, from -6 up to +6
+}
+
+// Run plays the simulation across the configured number of workers
+// The rounds are shared out in a fixed way, so the same configuration
+// always gives the same result
+func Run(cfg Config) (Result, error) {
+	if cfg.Strategy == nil {
+		return Result{}, fmt.Errorf("no strategy to simulate")
+	}
+	if cfg.Rounds < 1 {
+		return Result{}, fmt.Errorf("invalid number of rounds: %d", cfg.Rounds)
+	}
+	if err := cfg.Rules.Validate(); err != nil {
+		return Result{}, err
+	}
+	workers := cfg.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, cfg.Rounds)
+
+	results := make([]Result, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		rounds := cfg.Rounds / workers
+		if i < cfg.Rounds%workers {
+			rounds++
+		}
+
+		wg.Add(1)
+		go func(i, rounds int) {
+			defer wg.Done()
+			results[i], errs[i] = runWorker(cfg, cfg.Seed+int64(i), rounds)
+		}(i, rounds)
+	}
+	wg.Wait()
+
+	total := Result{Workers: workers, Seed: cfg.Seed, Count: cfg.Count.Name}
+	for i, result := range results {
+		if errs[i] != nil {
+			return Result{}, fmt.Errorf("worker %d: %v", i, errs[i])
+		}
+		total.add(result)
+	}
+	return total, nil
+}
+
+// add merges another worker's totals
+func (r *Result) add(other Result) {
+	r.Rounds += other.Rounds
+	r.Net += other.Net
+	r.SumSquares += other.SumSquares
+	r.Wins += other.Wins
+	r.Losses += other.Losses
+	r.Pushes += other.Pushes
+	r.Blackjacks += other.Blackjacks
+	for i, tally := range other.ByTrueCount {
+		r.ByTrueCount[i].Rounds += tally.Rounds
+		r.ByTrueCount[i].Net += tally.Net
+		r.ByTrueCount[i].SumSquares += tally.SumSquares
+	}
+}
+
+// runWorker plays rounds on a game of its own, shuffled from the given seed
+func runWorker(cfg Config, seed int64, rounds int) (Result, error) {
+	table := cfg.Rules
+	table.MinBet, table.MaxBet = Unit, Unit
+	opts := []game.Option{game.WithRules(table), game.WithSeed(seed)}
+	if cfg.Count.Name != "" {
+		opts = append(opts, game.WithCountingSystem(cfg.Count))
+	}
+	g, err := game.NewGame("Simulator", opts...)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for i := 0; i < rounds; i++ {
+		trueCount := int(math.Round(g.Counter().True()))
+		net, blackjack, err := playRound(g, cfg.Strategy)
+		if err != nil {
+			return Result{}, fmt.Errorf("round %d: %v", i+1, err)
+		}
+
+		units := float64(net) / Unit
+		result.Rounds++
+		result.Net += units
+		result.SumSquares += units * units
+		switch {
+		case net > 0:
+			result.Wins++
+		case net < 0:
+			result.Losses++
+		default:
+			result.Pushes++
+		}
+		if blackjack {
+			result.Blackjacks++
+		}
+		if cfg.Count.Name != "" {
+			tally := &result.ByTrueCount[min(max(trueCount, -maxTrueCount), maxTrueCount)+maxTrueCount]
+			tally.Rounds++
+			tally.Net += units
+			tally.SumSquares += units * units
+		}
+	}
+	return result, nil
+}
+
+// playRound plays one round with a flat bet, declining insurance as basic
+// strategy does, and returns the chips won or lost
+func playRound(g *game.Game, play Strategy) (net int, blackjack bool, err error) {
+	seat := g.Seats()[0]
+	seat.Player.Bankroll = 10 * Unit // Enough to split and double every hand
+	if err := g.PlaceBet(Unit); err != nil {
+		return 0, false, err
+	}
+	if err := g.StartRound(); err != nil {
+		return 0, false, err
+	}
+	blackjack = seat.Player.HasBlackjack()
+
+	for g.RoundInProgress() {
+		switch g.GetState() {
+		case game.InsuranceOffered:
+			err = g.DeclineInsurance()
+		case game.DealerTurn:
+			err = g.DealerPlay()
+		default:
+			var action strategy.Action
+			if action, err = play(g); err == nil {
+				err = act(g, action)
+			}
+		}
+		if err != nil {
+			return 0, false, err
+		}
+	}
+	return seat.RoundNet, blackjack, nil
+}
+
+// act makes the player's move in the game
+func act(g *game.Game, action strategy.Action) error {
+	switch action {
+	case strategy.Hit:
+		return g.PlayerHit()
+	case strategy.Stand:
+		return g.PlayerStand()
+	case strategy.Double:
+		return g.PlayerDouble()
+	case strategy.Split:
+		return g.PlayerSplit()
+	case strategy.Surrender:
+		return g.PlayerSurrender()
+	}
+	return fmt.Errorf("unknown action: %v", action)
+}
+
+// EV returns the expected value per round, as a fraction of the bet
+func (r Result) EV() float64 {
+	if r.Rounds == 0 {
+		return 0
+	}
+	return r.Net / float64(r.Rounds)
+}
+
+// SD returns the standard deviation of a round's result, in bets
+func (r Result) SD() float64 {
+	if r.Rounds < 2 {
+		return 0
+	}
+	n := float64(r.Rounds)
+	variance := (r.SumSquares - r.Net*r.Net/n) / (n - 1)
+	return math.Sqrt(max(variance, 0))
+}
+
+// z95 is the number of standard errors either side of an estimate that covers 95% of outcomes
+const z95 = 1.96
+
+// EVInterval returns the 95% confidence interval for the expected value
+func (r Result) EVInterval() (low, high float64) {
+	if r.Rounds == 0 {
+		return 0, 0
+	}
+	margin := z95 * r.SD() / math.Sqrt(float64(r.Rounds))
+	return r.EV() - margin, r.EV() + margin
+}
+
+// Frequency returns how often something happened per round, with the margin of its 95% confidence interval
+func (r Result) Frequency(count int) (rate, margin float64) {
+	if r.Rounds == 0 {
+		return 0, 0
+	}
+	rate = float64(count) / float64(r.Rounds)
+	return rate, z95 * math.Sqrt(rate*(1-rate)/float64(r.Rounds))
+}
+
+// String reports the results, one figure per line
+func (r Result) String() string {
+	low, high := r.EVInterval()
+	lines := []string{
+		fmt.Sprintf("Rounds played: %d (%s, seed %d)", r.Rounds, plural(r.Workers, "worker"), r.Seed),
+		fmt.Sprintf("Expected value: %+.3f%% per hand (95%% CI %+.3f%% to %+.3f%%)", 100*r.EV(), 100*low, 100*high),
+		fmt.Sprintf("Standard deviation: %.3f bets per hand", r.SD()),
+	}
+	for _, outcome := range []struct {
+		name  string
+		count int
+	}{{"Won", r.Wins}, {"Lost", r.Losses}, {"Pushed", r.Pushes}, {"BlackJack", r.Blackjacks}} {
+		rate, margin := r.Frequency(outcome.count)
+		lines = append(lines, fmt.Sprintf("%-10s %6.2f%% ± %.2f%%", outcome.name+":", 100*rate, 100*margin))
+	}
+	if r.Count != "" {
+		lines = append(lines, "", fmt.Sprintf("Expected value by %s true count before the deal:", r.Count))
+		lines = append(lines, r.trueCountLines()...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trueCountLines reports how often each true count came up and what it was worth
+func (r Result) trueCountLines() []string {
+	var lines []string
+	for i, tally := range r.ByTrueCount {
+		if tally.Rounds == 0 {
+			continue
+		}
+		label := fmt.Sprintf("%+d", i-maxTrueCount)
+		switch i {
+		case 0:
+			label += " or less"
+		case len(r.ByTrueCount) - 1:
+			label += " or more"
+		}
+		share, _ := r.Frequency(tally.Rounds)
+		low, high := tally.result().EVInterval()
+		lines = append(lines, fmt.Sprintf("%-10s %6.2f%% of rounds, EV %+.2f%% (95%% CI %+.2f%% to %+.2f%%)",
+			label, 100*share, 100*tally.result().EV(), 100*low, 100*high))
+	}
+	return lines
+}
+
+// plural formats a count with a noun, adding an "s" unless the count is one
+func plural(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -0,0 +1,169 @@
+package sim
+
+import (
+	"blackjack/internal/count"
+	"blackjack/internal/game"
+	"blackjack/internal/rules"
+	"blackjack/internal/strategy"
+	"math"
+	"strings"
+	"testing"
+)
+
+// sixDecks returns a common six-deck table for testing
+func sixDecks() rules.TableRules {
+	table := rules.DefaultTableRules()
+	table.Decks = 6
+	return table
+}
+
+// TestRunReproducible tests that the same seed and workers give the same result
+func TestRunReproducible(t *testing.T) {
+	cfg := Config{Rules: sixDecks(), Strategy: BasicStrategy, Rounds: 5000, Workers: 3, Seed: 42}
+	first, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := Run(cfg)
+	if first != second {
+		t.Errorf("Expected identical results, got %+v and %+v", first, second)
+	}
+	if first.Rounds != 5000 || first.Wins+first.Losses+first.Pushes != 5000 || first.Workers != 3 {
+		t.Errorf("Expected 5000 rounds across 3 workers, got %+v", first)
+	}
+
+	cfg.Seed = 43
+	if other, _ := Run(cfg); other == first {
+		t.Error("Expected a different seed to give different results")
+	}
+}
+
+// TestStrategiesCompare tests that basic strategy beats mimicking the dealer
+func TestStrategiesCompare(t *testing.T) {
+	cfg := Config{Rules: sixDecks(), Rounds: 40000, Workers: 2, Seed: 1}
+
+	cfg.Strategy = BasicStrategy
+	basic, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg.Strategy = MimicDealer
+	dealer, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if basic.EV() <= dealer.EV() {
+		t.Errorf("Expected basic strategy (%.4f) to beat mimicking the dealer (%.4f)", basic.EV(), dealer.EV())
+	}
+	if low, high := basic.EVInterval(); low > -0.004 || high < -0.004 {
+		t.Errorf("Expected the 95%% interval %.4f to %.4f to cover the known edge of about -0.4%%", low, high)
+	}
+	if rate, _ := basic.Frequency(basic.Blackjacks); math.Abs(rate-0.0475) > 0.005 {
+		t.Errorf("Expected BlackJacks in about 4.75%% of rounds, got %.4f", rate)
+	}
+}
+
+// TestEveryStrategyPlays tests that each named strategy completes rounds without illegal moves
+func TestEveryStrategyPlays(t *testing.T) {
+	table := sixDecks()
+	table.Surrender = rules.LateSurrender
+	table.ResplitAces = true
+	for _, name := range StrategyNames() {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Run(Config{Rules: table, Strategy: Strategies[name], Rounds: 2000, Workers: 1}); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestRunErrors tests refusing a simulation that cannot run
+func TestRunErrors(t *testing.T) {
+	bad := sixDecks()
+	bad.Decks = 3
+	always := func(*game.Game) (strategy.Action, error) { return strategy.Split, nil }
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"No strategy", Config{Rules: sixDecks(), Rounds: 10}},
+		{"No rounds", Config{Rules: sixDecks(), Strategy: BasicStrategy}},
+		{"Invalid rules", Config{Rules: bad, Strategy: BasicStrategy, Rounds: 10}},
+		{"Illegal moves", Config{Rules: sixDecks(), Strategy: always, Rounds: 100}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Run(test.cfg); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+// TestResultStatistics tests the figures reported from the totals
+func TestResultStatistics(t *testing.T) {
+	// Four rounds: +1, -1, +1.5 and 0
+	r := Result{Rounds: 4, Net: 1.5, SumSquares: 4.25, Wins: 2, Losses: 1, Pushes: 1, Blackjacks: 1, Workers: 1, Seed: 7}
+	if r.EV() != 0.375 {
+		t.Errorf("Expected EV 0.375, got %v", r.EV())
+	}
+	if sd := r.SD(); math.Abs(sd-math.Sqrt((4.25-1.5*1.5/4)/3)) > 1e-12 {
+		t.Errorf("Unexpected standard deviation %v", sd)
+	}
+	if low, high := r.EVInterval(); math.Abs((low+high)/2-r.EV()) > 1e-12 || high-low <= 0 {
+		t.Errorf("Expected an interval centred on the EV, got %v to %v", low, high)
+	}
+	if rate, margin := r.Frequency(r.Wins); rate != 0.5 || margin <= 0 {
+		t.Errorf("Expected a win rate of 0.5, got %v ± %v", rate, margin)
+	}
+	if (Result{}).EV() != 0 || (Result{}).SD() != 0 {
+		t.Error("Expected zero figures for an empty result")
+	}
+
+	report := r.String()
+	for _, expected := range []string{"Rounds played: 4 (1 worker, seed 7)", "Expected value: +37.500% per hand", "Won:", "BlackJack:"} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected report to contain %q, got:\n%s", expected, report)
+		}
+	}
+}
+
+// TestTrueCountBreakdown tests breaking the results down by the count before each deal
+func TestTrueCountBreakdown(t *testing.T) {
+	cfg := Config{Rules: sixDecks(), Strategy: BasicStrategy, Rounds: 100000, Workers: 2, Seed: 1, Count: count.HiLo}
+	r, err := Run(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rounds := 0
+	var low, high Tally // True counts of -2 or less, and +2 or more
+	for i, tally := range r.ByTrueCount {
+		rounds += tally.Rounds
+		bucket := &high
+		switch trueCount := i - maxTrueCount; {
+		case trueCount <= -2:
+			bucket = &low
+		case trueCount < 2:
+			continue
+		}
+		bucket.Rounds += tally.Rounds
+		bucket.Net += tally.Net
+	}
+	if rounds != r.Rounds {
+		t.Errorf("Expected every round in the breakdown, got %d of %d", rounds, r.Rounds)
+	}
+	if low.result().EV() >= high.result().EV() {
+		t.Errorf("Expected high counts (%.4f) to be worth more than low ones (%.4f)", high.result().EV(), low.result().EV())
+	}
+	if report := r.String(); !strings.Contains(report, "Expected value by Hi-Lo true count") || !strings.Contains(report, "+6 or more") {
+		t.Errorf("Expected the breakdown in the report, got:\n%s", report)
+	}
+
+	cfg.Count = count.System{}
+	if r, _ := Run(cfg); r.Count != "" || r.ByTrueCount[maxTrueCount].Rounds != 0 || strings.Contains(r.String(), "true count") {
+		t.Error("Expected no breakdown without a counting system")
+	}
+}
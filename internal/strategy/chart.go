@@ -0,0 +1,256 @@
+package strategy
+
+import (
+	"blackjack/internal/rules"
+	"fmt"
+	"strings"
+)
+
+// ace is the dealer upcard value of an Ace in the chart tables
+const ace = 11
+
+// NewChart generates the basic strategy chart for the table rules
+// The chart starts from the standard multi-deck strategy for a dealer who stands
+// on soft 17 and is adjusted for the deck count, H17, doubling and splitting
+// rules, surrender and the no-hole-card game
+func NewChart(table rules.TableRules) *Chart {
+	chart := &Chart{Rules: table}
+	for up := 2; up <= ace; up++ {
+		for total := 4; total <= 21; total++ {
+			chart.Hard[total][up] = hardPlay(table, total, up)
+		}
+		for total := 12; total <= 21; total++ {
+			chart.Soft[total][up] = softPlay(table, total, up)
+		}
+		for card := 2; card <= ace; card++ {
+			chart.Pairs[card][up] = pairPlay(table, card, up)
+		}
+	}
+	return chart
+}
+
+// between reports whether the dealer upcard is within the given values
+func between(up, low, high int) bool {
+	return up >= low && up <= high
+}
+
+// double returns a play that doubles when the table allows it on the total,
+// falling back to the given action
+func double(table rules.TableRules, total int, otherwise Action) Play {
+	if !table.Double.Allows(total) {
+		return Play{otherwise, otherwise}
+	}
+	return Play{Double, otherwise}
+}
+
+// always returns a play with no fallback
+func always(action Action) Play {
+	return Play{action, action}
+}
+
+// standOrHit returns Stand when the dealer upcard is within the given values, otherwise Hit
+func standOrHit(up, low, high int) Play {
+	if between(up, low, high) {
+		return always(Stand)
+	}
+	return always(Hit)
+}
+
+// noHoleCard reports whether the dealer's upcard may hide a BlackJack that
+// takes doubled and split bets too, because the dealer doesn't peek
+func noHoleCard(table rules.TableRules, up int) bool {
+	return !table.DealerPeeks && up >= 10
+}
+
+// hardPlay works out the play for a hard total
+func hardPlay(table rules.TableRules, total, up int) Play {
+	var play Play
+	switch {
+	case total >= 17:
+		play = always(Stand)
+	case total >= 13:
+		play = standOrHit(up, 2, 6)
+	case total == 12:
+		play = standOrHit(up, 4, 6)
+	case total == 11:
+		play = always(Hit)
+		if up < 10 || (up == 10 && table.DealerPeeks) ||
+			(up == ace && table.DealerPeeks && (table.DealerHitsSoft17 || table.Decks <= 2)) {
+			play = double(table, total, Hit)
+		}
+	case total == 10:
+		play = always(Hit)
+		if up <= 9 {
+			play = double(table, total, Hit)
+		}
+	case total == 9:
+		play = always(Hit)
+		if between(up, 3, 6) || (up == 2 && table.Decks <= 2) {
+			play = double(table, total, Hit)
+		}
+	default:
+		play = always(Hit)
+	}
+
+	if surrenderHard(table, total, up) {
+		play = Play{Surrender, play.Fallback}
+	}
+	return play
+}
+
+// surrenderHard reports whether a hard total should be surrendered against the upcard
+func surrenderHard(table rules.TableRules, total, up int) bool {
+	switch table.Surrender {
+	case rules.EarlySurrender:
+		// Giving up before the dealer checks saves half the bet against every BlackJack
+		switch up {
+		case ace:
+			return between(total, 5, 7) || between(total, 12, 17)
+		case 10:
+			return between(total, 14, 16)
+		}
+	case rules.LateSurrender:
+		switch up {
+		case ace:
+			return total == 16 || (table.DealerHitsSoft17 && (total == 15 || total == 17))
+		case 10:
+			return total == 15 || total == 16
+		case 9:
+			return total == 16 && table.Decks >= 4
+		}
+	}
+	return false
+}
+
+// softPlay works out the play for a soft total
+func softPlay(table rules.TableRules, total, up int) Play {
+	switch {
+	case total >= 20:
+		return always(Stand)
+	case total == 19:
+		if up == 6 && (table.DealerHitsSoft17 || table.Decks == 1) {
+			return double(table, total, Stand)
+		}
+		return always(Stand)
+	case total == 18:
+		switch {
+		case between(up, 3, 6) || (up == 2 && table.DealerHitsSoft17):
+			return double(table, total, Stand)
+		case between(up, 2, 8):
+			return always(Stand)
+		default:
+			return always(Hit)
+		}
+	case total == 17:
+		if between(up, 3, 6) {
+			return double(table, total, Hit)
+		}
+	case total >= 15:
+		if between(up, 4, 6) {
+			return double(table, total, Hit)
+		}
+	case total >= 13:
+		if between(up, 5, 6) {
+			return double(table, total, Hit)
+		}
+	}
+	return always(Hit)
+}
+
+// pairPlay works out whether a pair of the given card value should be split
+// Pairs that shouldn't be split are left as Hit and played by their total
+func pairPlay(table rules.TableRules, card, up int) Play {
+	if table.MaxSplits == 0 {
+		return always(Hit)
+	}
+
+	das := table.DoubleAfterSplit
+	var split bool
+	switch card {
+	case ace:
+		split = !(noHoleCard(table, up) && up == ace)
+	case 10, 5:
+		split = false // Stand on 20 and double 10 instead
+	case 9:
+		split = between(up, 2, 6) || between(up, 8, 9)
+	case 8:
+		split = !noHoleCard(table, up)
+	case 7:
+		split = between(up, 2, 7)
+	case 6:
+		split = between(up, 3, 6) || (das && (up == 2 || (up == 7 && table.Decks == 1)))
+	case 4:
+		split = das && between(up, 5, 6)
+	default: // Twos and threes
+		split = between(up, 4, 7) || (das && between(up, 2, 3))
+	}
+
+	switch {
+	case !split:
+		return always(Hit)
+	case card == 8 && up == ace && surrenderEights(table):
+		return Play{Surrender, Split}
+	default:
+		return always(Split)
+	}
+}
+
+// surrenderEights reports whether a pair of eights should be surrendered
+// against an Ace rather than split
+func surrenderEights(table rules.TableRules) bool {
+	return table.Surrender == rules.EarlySurrender ||
+		(table.Surrender == rules.LateSurrender && table.DealerHitsSoft17)
+}
+
+// String renders the chart as a grid of codes, one row per hand and one column per dealer upcard
+// Pairs that shouldn't be split show the play for their total
+func (c *Chart) String() string {
+	var b strings.Builder
+	line := func(label string, cell func(up int) string) {
+		cells := fmt.Sprintf("%-8s", label)
+		for up := 2; up <= ace; up++ {
+			cells += fmt.Sprintf("%-3s", cell(up))
+		}
+		b.WriteString(strings.TrimRight(cells, " ") + "\n")
+	}
+	header := func(title string) {
+		line(title, func(up int) string {
+			if up == ace {
+				return "A"
+			}
+			return fmt.Sprint(up)
+		})
+	}
+	row := func(label string, plays func(up int) Play) {
+		line(label, func(up int) string { return plays(up).Code() })
+	}
+
+	header("Hard")
+	for total := 5; total <= 20; total++ {
+		row(fmt.Sprint(total), func(up int) Play { return c.Hard[total][up] })
+	}
+	b.WriteString("\n")
+	header("Soft")
+	for total := 13; total <= 20; total++ {
+		row(fmt.Sprintf("A,%d", total-11), func(up int) Play { return c.Soft[total][up] })
+	}
+	b.WriteString("\n")
+	header("Pairs")
+	for card := ace; card >= 2; card-- {
+		label := fmt.Sprintf("%d,%d", card, card)
+		if card == ace {
+			label = "A,A"
+		}
+		row(label, func(up int) Play {
+			if play := c.Pairs[card][up]; play.splits() {
+				return play
+			}
+			if card == ace {
+				return c.Soft[12][up]
+			}
+			return c.Hard[card*2][up]
+		})
+	}
+	b.WriteString("\nH hit, S stand, P split, D double (else hit), Ds double (else stand), Rh/Rs/Rp surrender (else hit/stand/split)")
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package strategy
+
+import (
+	"blackjack/internal/rules"
+	"strings"
+	"testing"
+)
+
+// TestNewChart tests that charts change with the table rules
+func TestNewChart(t *testing.T) {
+	shoe := rules.DefaultTableRules()
+	shoe.Decks = 6
+
+	tests := []struct {
+		name     string
+		change   func(*rules.TableRules)
+		cards    string
+		upcard   string
+		expected string
+	}{
+		{"Six decks, 11 against an Ace", func(r *rules.TableRules) {}, "6H 5S", "AD", "H"},
+		{"Single deck, 11 against an Ace", func(r *rules.TableRules) { r.Decks = 1 }, "6H 5S", "AD", "D"},
+		{"H17, 11 against an Ace", func(r *rules.TableRules) { r.DealerHitsSoft17 = true }, "6H 5S", "AD", "D"},
+		{"Six decks, 9 against a two", func(r *rules.TableRules) {}, "5H 4S", "2D", "H"},
+		{"Two decks, 9 against a two", func(r *rules.TableRules) { r.Decks = 2 }, "5H 4S", "2D", "D"},
+		{"S17, soft 18 against a two", func(r *rules.TableRules) {}, "AH 7S", "2D", "S"},
+		{"H17, soft 18 against a two", func(r *rules.TableRules) { r.DealerHitsSoft17 = true }, "AH 7S", "2D", "Ds"},
+		{"H17, soft 19 against a six", func(r *rules.TableRules) { r.DealerHitsSoft17 = true }, "AH 8S", "6D", "Ds"},
+		{"Double 10-11 only, 9 against a four", func(r *rules.TableRules) { r.Double = rules.DoubleTenToEleven }, "5H 4S", "4D", "H"},
+		{"Double 9-11 only, soft 17 against a four", func(r *rules.TableRules) { r.Double = rules.DoubleNineToEleven }, "AH 6S", "4D", "H"},
+		{"DAS, twos against a two", func(r *rules.TableRules) {}, "2H 2S", "2D", "P"},
+		{"No DAS, twos against a two", func(r *rules.TableRules) { r.DoubleAfterSplit = false }, "2H 2S", "2D", "H"},
+		{"Six decks, sixes against a seven", func(r *rules.TableRules) {}, "6H 6S", "7D", "H"},
+		{"Single deck DAS, sixes against a seven", func(r *rules.TableRules) { r.Decks = 1 }, "6H 6S", "7D", "P"},
+		{"No DAS, fours against a five", func(r *rules.TableRules) { r.DoubleAfterSplit = false }, "4H 4S", "5D", "H"},
+		{"No splitting, eights", func(r *rules.TableRules) { r.MaxSplits = 0 }, "8H 8S", "6D", "S"},
+		{"No surrender, 16 against a ten", func(r *rules.TableRules) {}, "TH 6S", "KD", "H"},
+		{"Late surrender, 16 against a ten", func(r *rules.TableRules) { r.Surrender = rules.LateSurrender }, "TH 6S", "KD", "Rh"},
+		{"Late surrender, 15 against a ten", func(r *rules.TableRules) { r.Surrender = rules.LateSurrender }, "9H 6S", "KD", "Rh"},
+		{"Late surrender, 17 against an Ace", func(r *rules.TableRules) { r.Surrender = rules.LateSurrender }, "TH 7S", "AD", "S"},
+		{"Late surrender H17, 17 against an Ace", func(r *rules.TableRules) {
+			r.Surrender = rules.LateSurrender
+			r.DealerHitsSoft17 = true
+		}, "TH 7S", "AD", "Rs"},
+		{"Late surrender H17, eights against an Ace", func(r *rules.TableRules) {
+			r.Surrender = rules.LateSurrender
+			r.DealerHitsSoft17 = true
+		}, "8H 8S", "AD", "Rp"},
+		{"Late surrender, eights against a ten", func(r *rules.TableRules) { r.Surrender = rules.LateSurrender }, "8H 8S", "TD", "P"},
+		{"Early surrender, 7 against an Ace", func(r *rules.TableRules) { r.Surrender = rules.EarlySurrender }, "4H 3S", "AD", "Rh"},
+		{"Early surrender, 14 against a ten", func(r *rules.TableRules) { r.Surrender = rules.EarlySurrender }, "9H 5S", "TD", "Rh"},
+		{"No hole card, 11 against a ten", func(r *rules.TableRules) { r.DealerPeeks = false }, "6H 5S", "TD", "H"},
+		{"No hole card, eights against a ten", func(r *rules.TableRules) { r.DealerPeeks = false }, "8H 8S", "TD", "H"},
+		{"No hole card, Aces against an Ace", func(r *rules.TableRules) { r.DealerPeeks = false }, "AH AS", "AD", "H"},
+		{"No hole card, Aces against a ten", func(r *rules.TableRules) { r.DealerPeeks = false }, "AH AS", "TD", "P"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := shoe
+			test.change(&table)
+			chart := NewChart(table)
+			play := chart.Lookup(mustCards(t, test.cards), mustCards(t, test.upcard)[0])
+			if got := play.Code(); got != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
+
+// TestChartString tests rendering a chart
+func TestChartString(t *testing.T) {
+	table := rules.DefaultTableRules()
+	table.Decks = 6
+	table.Surrender = rules.LateSurrender
+	text := NewChart(table).String()
+
+	for _, expected := range []string{
+		"Hard    2  3  4  5  6  7  8  9  10 A\n",
+		"16      S  S  S  S  S  H  H  Rh Rh Rh\n",
+		"A,7     S  Ds Ds Ds Ds S  S  H  H  H\n",
+		"5,5     D  D  D  D  D  D  D  D  H  H\n",
+		"8,8     P  P  P  P  P  P  P  P  P  P\n",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected chart to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Package strategy works out the basic strategy play for any BlackJack hand
+package strategy
+
+import (
+	"blackjack/internal/deck"
+	"blackjack/internal/player"
+	"blackjack/internal/rules"
+	"strings"
+	"sync"
+)
+
+// Action is a move a player can make on their turn
+type Action int
+
+const (
+	Hit Action = iota
+	Stand
+	Double
+	Split
+	Surrender
+)
+
+// actionNames holds the names of the actions, matching the game's commands
+var actionNames = map[Action]string{
+	Hit:       "hit",
+	Stand:     "stand",
+	Double:    "double",
+	Split:     "split",
+	Surrender: "surrender",
+}
+
+// actionCodes holds the abbreviations used in strategy charts
+var actionCodes = map[Action]string{Hit: "H", Stand: "S", Double: "D", Split: "P", Surrender: "R"}
+
+// String returns the name of the action (e.g., "double")
+func (a Action) String() string {
+	if name, ok := actionNames[a]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// allowed reports whether the action is legal right now
+// Hit and Stand are always allowed while a hand is being played
+func (a Action) allowed(legal rules.Actions) bool {
+	switch a {
+	case Double:
+		return legal.Double
+	case Split:
+		return legal.Split
+	case Surrender:
+		return legal.Surrender
+	default:
+		return true
+	}
+}
+
+// Play is one entry in a strategy chart: the best action, and what to do
+// instead when it isn't allowed (e.g., "double if allowed, otherwise hit")
+type Play struct {
+	Action   Action
+	Fallback Action
+}
+
+// Resolve returns the action to take given which optional actions are legal
+func (p Play) Resolve(legal rules.Actions) Action {
+	if p.Action.allowed(legal) {
+		return p.Action
+	}
+	return p.Fallback
+}
+
+// splits reports whether the entry calls for splitting, first choice or not
+func (p Play) splits() bool {
+	return p.Action == Split || p.Fallback == Split
+}
+
+// Code returns the chart abbreviation for the play (e.g., "Ds" for double, else stand)
+func (p Play) Code() string {
+	code := actionCodes[p.Action]
+	if p.Action == p.Fallback || (p.Action == Double && p.Fallback == Hit) {
+		return code // A plain D means double, else hit
+	}
+	return code + strings.ToLower(actionCodes[p.Fallback])
+}
+
+// Chart is a basic strategy chart for one set of table rules
+// Totals are indexed by the player's total and the dealer's upcard value (2 to 11, with the Ace as 11)
+type Chart struct {
+	Hard  [22][12]Play // Hard totals from 4 to 21
+	Soft  [22][12]Play // Soft totals from 12 (two Aces) to 21
+	Pairs [12][12]Play // Pairs by card value; entries that don't split are played by their total
+	Rules rules.TableRules
+}
+
+// Lookup returns the chart entry for a set of cards against the dealer's upcard,
+// assuming a pair may be split
+func (c *Chart) Lookup(cards []deck.Card, upcard deck.Card) Play {
+	return c.lookup(cards, upcard, true)
+}
+
+// Action returns the basic strategy action for a set of cards against the
+// dealer's upcard, falling back when the best action isn't legal
+func (c *Chart) Action(cards []deck.Card, upcard deck.Card, legal rules.Actions) Action {
+	return c.lookup(cards, upcard, legal.Split).Resolve(legal)
+}
+
+// lookup finds the chart entry, using the pair table only when the pair may be split
+func (c *Chart) lookup(cards []deck.Card, upcard deck.Card, canSplit bool) Play {
+	value := player.Evaluate(cards)
+	up := upcard.Value()
+	if value.Pair && canSplit {
+		if play := c.Pairs[cards[0].Value()][up]; play.splits() {
+			return play
+		}
+	}
+
+	switch {
+	case value.Total > 21:
+		return Play{Stand, Stand} // Nothing left to decide on a busted hand
+	case value.Soft:
+		return c.Soft[value.Total][up]
+	case value.Total < 4:
+		return Play{Hit, Hit}
+	default:
+		return c.Hard[value.Total][up]
+	}
+}
+
+// charts caches the chart generated for each set of table rules
+var charts sync.Map
+
+// For returns the basic strategy chart for the table rules, generating it the first time it is needed
+func For(table rules.TableRules) *Chart {
+	if chart, ok := charts.Load(table); ok {
+		return chart.(*Chart)
+	}
+	chart, _ := charts.LoadOrStore(table, NewChart(table))
+	return chart.(*Chart)
+}
+
+// Recommend returns the basic strategy action for a hand against the dealer's upcard
+// under the table rules, given which optional actions are legal
+func Recommend(hand *player.Hand, upcard deck.Card, table rules.TableRules, legal rules.Actions) Action {
+	return For(table).Action(hand.Cards, upcard, legal)
+}
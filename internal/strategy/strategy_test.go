@@ -0,0 +1,111 @@
+package strategy
+
+import (
+	"blackjack/internal/deck"
+	"blackjack/internal/player"
+	"blackjack/internal/rules"
+	"testing"
+)
+
+// mustCards parses a list of cards for a test
+func mustCards(t *testing.T, s string) []deck.Card {
+	t.Helper()
+	cards, err := deck.ParseCards(s)
+	if err != nil {
+		t.Fatalf("Bad test cards %q: %v", s, err)
+	}
+	return cards
+}
+
+// TestPlayResolve tests falling back when the best action isn't legal
+func TestPlayResolve(t *testing.T) {
+	tests := []struct {
+		play     Play
+		legal    rules.Actions
+		expected Action
+		code     string
+	}{
+		{Play{Double, Hit}, rules.Actions{Double: true}, Double, "D"},
+		{Play{Double, Hit}, rules.Actions{}, Hit, "D"},
+		{Play{Double, Stand}, rules.Actions{}, Stand, "Ds"},
+		{Play{Surrender, Hit}, rules.Actions{Surrender: true}, Surrender, "Rh"},
+		{Play{Surrender, Split}, rules.Actions{Split: true}, Split, "Rp"},
+		{Play{Split, Split}, rules.Actions{Split: true}, Split, "P"},
+		{Play{Stand, Stand}, rules.Actions{}, Stand, "S"},
+	}
+	for _, test := range tests {
+		if got := test.play.Resolve(test.legal); got != test.expected {
+			t.Errorf("%s with %+v: expected %s, got %s", test.play.Code(), test.legal, test.expected, got)
+		}
+		if got := test.play.Code(); got != test.code {
+			t.Errorf("Expected code %s, got %s", test.code, got)
+		}
+	}
+}
+
+// TestRecommend tests the recommended action for hands at a default table
+func TestRecommend(t *testing.T) {
+	table := rules.DefaultTableRules()
+	all := rules.Actions{Double: true, Split: true, Surrender: true}
+
+	tests := []struct {
+		name     string
+		cards    string
+		upcard   string
+		legal    rules.Actions
+		expected Action
+	}{
+		{"Hard 16 against a ten", "TH 6S", "KD", all, Hit},
+		{"Hard 13 against a six", "9H 4S", "6D", all, Stand},
+		{"Hard 11 doubles", "6H 5S", "TD", all, Double},
+		{"Hard 11 with three cards hits", "2H 4S 5C", "TD", rules.Actions{}, Hit},
+		{"Soft 18 doubles against a four", "AH 7S", "4D", all, Double},
+		{"Soft 18 stands when it can't double", "AH 3S 4C", "4D", rules.Actions{}, Stand},
+		{"Soft 18 hits against a nine", "AH 7S", "9D", all, Hit},
+		{"Eights split", "8H 8S", "TD", all, Split},
+		{"Eights that can't be split play as 16", "8H 8S", "6D", rules.Actions{Double: true}, Stand},
+		{"Fives double as 10", "5H 5S", "6D", all, Double},
+		{"Tens stand", "KH QS", "6D", all, Stand},
+		{"Aces split", "AH AS", "AD", all, Split},
+		{"Aces that can't be split hit", "AH AS", "6D", rules.Actions{}, Hit},
+		{"Busted hand", "KH QS 5D", "6D", rules.Actions{}, Stand},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hand := player.NewHand()
+			for _, card := range mustCards(t, test.cards) {
+				hand.AddCard(card)
+			}
+			upcard := mustCards(t, test.upcard)[0]
+			if got := Recommend(hand, upcard, table, test.legal); got != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
+
+// TestFor tests that charts are generated once per set of rules
+func TestFor(t *testing.T) {
+	table := rules.DefaultTableRules()
+	if For(table) != For(table) {
+		t.Error("Expected the same chart for the same rules")
+	}
+	table.DealerHitsSoft17 = true
+	if chart := For(table); chart == For(rules.DefaultTableRules()) || !chart.Rules.DealerHitsSoft17 {
+		t.Error("Expected a separate chart for H17")
+	}
+}
+
+// TestActionString tests the action names
+func TestActionString(t *testing.T) {
+	names := []string{"hit", "stand", "double", "split", "surrender"}
+	for i, name := range names {
+		if got := Action(i).String(); got != name {
+			t.Errorf("Expected %s, got %s", name, got)
+		}
+	}
+	if Action(99).String() != "unknown" {
+		t.Error("Expected unknown action name")
+	}
+}